@@ -0,0 +1,67 @@
+package errors
+
+import (
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+)
+
+// Code classifies an error independently of the transport, it picks the
+// default HTTP and gRPC status of the error
+type Code string
+
+const (
+	CodeUnknown            Code = "unknown"
+	CodeInvalidArgument    Code = "invalid_argument"
+	CodeUnauthenticated    Code = "unauthenticated"
+	CodePermissionDenied   Code = "permission_denied"
+	CodeNotFound           Code = "not_found"
+	CodeAlreadyExists      Code = "already_exists"
+	CodeFailedPrecondition Code = "failed_precondition"
+	CodeResourceExhausted  Code = "resource_exhausted"
+	CodeCanceled           Code = "canceled"
+	CodeDeadlineExceeded   Code = "deadline_exceeded"
+	CodeUnimplemented      Code = "unimplemented"
+	CodeUnavailable        Code = "unavailable"
+	CodeInternal           Code = "internal"
+)
+
+type statusPair struct {
+	http int
+	grpc codes.Code
+}
+
+// statuses follows the grpc-gateway mapping between gRPC and HTTP statuses
+var statuses = map[Code]statusPair{
+	CodeUnknown:            {http.StatusInternalServerError, codes.Unknown},
+	CodeInvalidArgument:    {http.StatusBadRequest, codes.InvalidArgument},
+	CodeUnauthenticated:    {http.StatusUnauthorized, codes.Unauthenticated},
+	CodePermissionDenied:   {http.StatusForbidden, codes.PermissionDenied},
+	CodeNotFound:           {http.StatusNotFound, codes.NotFound},
+	CodeAlreadyExists:      {http.StatusConflict, codes.AlreadyExists},
+	CodeFailedPrecondition: {http.StatusBadRequest, codes.FailedPrecondition},
+	CodeResourceExhausted:  {http.StatusTooManyRequests, codes.ResourceExhausted},
+	CodeCanceled:           {499, codes.Canceled},
+	CodeDeadlineExceeded:   {http.StatusGatewayTimeout, codes.DeadlineExceeded},
+	CodeUnimplemented:      {http.StatusNotImplemented, codes.Unimplemented},
+	CodeUnavailable:        {http.StatusServiceUnavailable, codes.Unavailable},
+	CodeInternal:           {http.StatusInternalServerError, codes.Internal},
+}
+
+// HTTPStatus returns the default HTTP status of the code, 500 for codes
+// defined outside this package
+func (c Code) HTTPStatus() int {
+	if s, ok := statuses[c]; ok {
+		return s.http
+	}
+	return http.StatusInternalServerError
+}
+
+// GRPCCode returns the default gRPC code of the code, Unknown for codes
+// defined outside this package
+func (c Code) GRPCCode() codes.Code {
+	if s, ok := statuses[c]; ok {
+		return s.grpc
+	}
+	return codes.Unknown
+}
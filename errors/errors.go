@@ -0,0 +1,310 @@
+// Package errors provides a structured error carrying a code, HTTP and gRPC
+// statuses, metadata, a stack trace and a cause. It is understood by
+// SentryObs.CaptureError and ContextLogger.Err.
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io"
+	"maps"
+	"runtime"
+
+	pkgerrors "github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+const maxStackDepth = 32
+
+// Error is a structured error, build it with New or Wrap and the With
+// methods
+type Error struct {
+	code       Code
+	message    string
+	httpStatus int
+	grpcCode   codes.Code
+	metadata   map[string]interface{}
+	cause      error
+	stack      []uintptr
+	// origin is the error the With methods copied this one from, so a copy
+	// of a sentinel still matches it
+	origin *Error
+}
+
+// New returns an error with code and message, its statuses default to the
+// ones of code
+func New(code Code, message string) *Error {
+	return newError(code, message, nil)
+}
+
+func Newf(code Code, format string, args ...interface{}) *Error {
+	return newError(code, fmt.Sprintf(format, args...), nil)
+}
+
+// Wrap annotates err with message, nil when err is nil. An empty code keeps
+// the code, statuses and metadata of the nearest Error wrapped by err. Like
+// pkg/errors it returns an error, so a nil err stays nil in the caller.
+func Wrap(err error, code Code, message string) error {
+	if err == nil {
+		return nil
+	}
+	return newError(code, message, err)
+}
+
+func Wrapf(err error, code Code, format string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return newError(code, fmt.Sprintf(format, args...), err)
+}
+
+// WithHTTPStatus, WithGRPCCode and WithMetadata apply the Error method of the
+// same name to err, nil when err is nil. An err that is not an Error is
+// wrapped first, keeping the details of the nearest Error it wraps.
+func WithHTTPStatus(err error, status int) error {
+	if err == nil {
+		return nil
+	}
+	return asError(err).WithHTTPStatus(status)
+}
+
+func WithGRPCCode(err error, code codes.Code) error {
+	if err == nil {
+		return nil
+	}
+	return asError(err).WithGRPCCode(code)
+}
+
+func WithMetadata(err error, key string, value interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return asError(err).WithMetadata(key, value)
+}
+
+func asError(err error) *Error {
+	if e, ok := err.(*Error); ok {
+		return e
+	}
+	e := newError("", "", err)
+	// skip the With function calling asError, the stack starts at its caller
+	if len(e.stack) > 1 {
+		e.stack = e.stack[1:]
+	}
+	return e
+}
+
+func newError(code Code, message string, cause error) *Error {
+	e := &Error{
+		code:    code,
+		message: message,
+		cause:   cause,
+		stack:   callers(),
+	}
+
+	var inner *Error
+	if code == "" && stderrors.As(cause, &inner) {
+		e.code = inner.code
+		e.httpStatus = inner.httpStatus
+		e.grpcCode = inner.grpcCode
+		e.metadata = maps.Clone(inner.metadata)
+		return e
+	}
+
+	if e.code == "" {
+		e.code = CodeUnknown
+	}
+	e.httpStatus = e.code.HTTPStatus()
+	e.grpcCode = e.code.GRPCCode()
+
+	return e
+}
+
+func callers() []uintptr {
+	pcs := make([]uintptr, maxStackDepth)
+	// skip runtime.Callers, callers, newError and the exported constructor
+	n := runtime.Callers(4, pcs)
+	return pcs[:n]
+}
+
+// WithHTTPStatus returns a copy of the error with status instead of the HTTP
+// status picked from the code. Like the other With methods it leaves e
+// untouched, so it is safe on package level sentinels.
+func (e *Error) WithHTTPStatus(status int) *Error {
+	c := e.copy()
+	c.httpStatus = status
+	return c
+}
+
+// WithGRPCCode returns a copy of the error with code instead of the gRPC code
+// picked from the code
+func (e *Error) WithGRPCCode(code codes.Code) *Error {
+	c := e.copy()
+	c.grpcCode = code
+	return c
+}
+
+// WithMetadata returns a copy of the error with key added to the metadata
+// reported with it
+func (e *Error) WithMetadata(key string, value interface{}) *Error {
+	c := e.copy()
+	if c.metadata == nil {
+		c.metadata = map[string]interface{}{}
+	}
+	c.metadata[key] = value
+	return c
+}
+
+func (e *Error) copy() *Error {
+	c := *e
+	c.metadata = maps.Clone(e.metadata)
+	c.origin = e.root()
+	return &c
+}
+
+func (e *Error) root() *Error {
+	if e.origin != nil {
+		return e.origin
+	}
+	return e
+}
+
+func (e *Error) Error() string {
+	if e.cause == nil {
+		return e.message
+	}
+	if e.message == "" {
+		return e.cause.Error()
+	}
+	return e.message + ": " + e.cause.Error()
+}
+
+func (e *Error) Code() Code {
+	return e.code
+}
+
+func (e *Error) Message() string {
+	return e.message
+}
+
+func (e *Error) HTTPStatus() int {
+	return e.httpStatus
+}
+
+func (e *Error) GRPCCode() codes.Code {
+	return e.grpcCode
+}
+
+// GetMetadata returns the metadata of the error, SentryObs reports it as
+// extras
+func (e *Error) GetMetadata() map[string]interface{} {
+	return e.metadata
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is matches target itself, the copies the With methods made of it and, when
+// target has no message, any Error with the same code, so
+// errors.Is(err, New(CodeNotFound, "")) matches every not found error
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	if e.root() == t.root() {
+		return true
+	}
+	return t.message == "" && t.code == e.code
+}
+
+// GRPCStatus lets grpc status.FromError and status.Code read the error
+func (e *Error) GRPCStatus() *grpcstatus.Status {
+	return grpcstatus.New(e.grpcCode, e.Error())
+}
+
+// StackTrace returns where the error was created, in the pkg/errors format
+// sentry-go and %+v understand
+func (e *Error) StackTrace() pkgerrors.StackTrace {
+	frames := make(pkgerrors.StackTrace, len(e.stack))
+	for i, pc := range e.stack {
+		frames[i] = pkgerrors.Frame(pc)
+	}
+	return frames
+}
+
+// Format prints the stack trace with %+v, like pkg/errors
+func (e *Error) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if e.cause != nil {
+				fmt.Fprintf(s, "%+v\n", e.cause)
+			}
+			io.WriteString(s, e.message)
+			e.StackTrace().Format(s, verb)
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, e.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	}
+}
+
+// Fields returns the code, statuses and metadata of the nearest Error in the
+// chain of err, nil when there is none. Log and error reporters use it to
+// attach the error details.
+func Fields(err error) map[string]interface{} {
+	var e *Error
+	if !stderrors.As(err, &e) {
+		return nil
+	}
+
+	fields := map[string]interface{}{
+		"error_code":  string(e.code),
+		"http_status": e.httpStatus,
+		"grpc_code":   e.grpcCode.String(),
+	}
+	if len(e.metadata) > 0 {
+		fields["error_metadata"] = maps.Clone(e.metadata)
+	}
+	return fields
+}
+
+// CodeOf returns the code of the nearest Error in the chain of err,
+// CodeUnknown when there is none
+func CodeOf(err error) Code {
+	var e *Error
+	if stderrors.As(err, &e) {
+		return e.code
+	}
+	return CodeUnknown
+}
+
+// HTTPStatus returns the HTTP status of the nearest Error in the chain of
+// err, 500 when there is none
+func HTTPStatus(err error) int {
+	var e *Error
+	if stderrors.As(err, &e) {
+		return e.httpStatus
+	}
+	return CodeUnknown.HTTPStatus()
+}
+
+// Is, As and Unwrap are the standard library functions, so callers only need
+// this package
+func Is(err, target error) bool {
+	return stderrors.Is(err, target)
+}
+
+func As(err error, target interface{}) bool {
+	return stderrors.As(err, target)
+}
+
+func Unwrap(err error) error {
+	return stderrors.Unwrap(err)
+}
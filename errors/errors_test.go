@@ -0,0 +1,132 @@
+package errors
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrUserNotFound  = New(CodeNotFound, "user not found")
+	ErrOrderNotFound = New(CodeNotFound, "order not found")
+)
+
+func TestNew(t *testing.T) {
+	err := New(CodeNotFound, "user not found").WithMetadata("user_id", 42)
+
+	require.Equal(t, "user not found", err.Error())
+	require.Equal(t, CodeNotFound, err.Code())
+	require.Equal(t, http.StatusNotFound, err.HTTPStatus())
+	require.Equal(t, codes.NotFound, err.GRPCCode())
+	require.Equal(t, map[string]interface{}{"user_id": 42}, err.GetMetadata())
+	require.Nil(t, err.Unwrap())
+
+	frames := err.StackTrace()
+	require.NotEmpty(t, frames)
+	require.Equal(t, "TestNew", fmt.Sprintf("%n", frames[0]))
+
+	require.Equal(t, CodeUnknown, New("", "boom").Code())
+	require.Equal(t, http.StatusInternalServerError, New(Code("custom"), "boom").HTTPStatus())
+}
+
+func TestWrap(t *testing.T) {
+	var err error = Wrap(nil, CodeInternal, "query")
+	require.True(t, err == nil)
+	err = Wrapf(nil, CodeInternal, "query %s", "users")
+	require.True(t, err == nil)
+	err = WithMetadata(nil, "user_id", 42)
+	require.True(t, err == nil)
+
+	cause := context.DeadlineExceeded
+	err = WithHTTPStatus(Wrapf(cause, CodeUnavailable, "query %s", "users"), http.StatusBadGateway)
+
+	require.Equal(t, "query users: context deadline exceeded", err.Error())
+	require.Equal(t, http.StatusBadGateway, HTTPStatus(err))
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	require.True(t, Is(err, context.DeadlineExceeded))
+
+	// an empty code keeps the details of the wrapped error
+	inner := New(CodeNotFound, "user not found").WithMetadata("user_id", 42).WithGRPCCode(codes.FailedPrecondition)
+	var outer *Error
+	require.True(t, As(Wrap(fmt.Errorf("lookup: %w", inner), "", "get profile"), &outer))
+	require.Equal(t, CodeNotFound, outer.Code())
+	require.Equal(t, codes.FailedPrecondition, outer.GRPCCode())
+	require.Equal(t, inner.GetMetadata(), outer.GetMetadata())
+
+	public := outer.WithMetadata("profile", "public")
+	require.Equal(t, "public", public.GetMetadata()["profile"])
+	require.NotContains(t, outer.GetMetadata(), "profile")
+	require.NotContains(t, inner.GetMetadata(), "profile")
+
+	// the package level With functions wrap errors from elsewhere
+	var plain *Error
+	require.True(t, As(WithMetadata(fmt.Errorf("lookup: %w", inner), "shard", 3), &plain))
+	require.Equal(t, "lookup: user not found", plain.Error())
+	require.Equal(t, CodeNotFound, plain.Code())
+	require.Equal(t, map[string]interface{}{"user_id": 42, "shard": 3}, plain.GetMetadata())
+	require.Equal(t, "TestWrap", fmt.Sprintf("%n", plain.StackTrace()[0]))
+}
+
+func TestIsAs(t *testing.T) {
+	err := fmt.Errorf("handler: %w", Wrap(stderrors.New("no rows"), CodeNotFound, "user 42 not found"))
+
+	// a target without message matches by code
+	require.True(t, Is(err, New(CodeNotFound, "")))
+	require.False(t, Is(err, New(CodeInternal, "")))
+	require.False(t, Is(err, ErrUserNotFound))
+	require.False(t, Is(stderrors.New("no rows"), ErrUserNotFound))
+
+	// sentinels sharing a code stay distinct, copies still match them
+	user := fmt.Errorf("handler: %w", ErrUserNotFound.WithMetadata("user_id", 42))
+	require.True(t, Is(user, ErrUserNotFound))
+	require.False(t, Is(user, ErrOrderNotFound))
+	require.True(t, Is(Wrap(ErrOrderNotFound, "", "get order"), ErrOrderNotFound))
+	require.Nil(t, ErrUserNotFound.GetMetadata())
+
+	var target *Error
+	require.True(t, As(err, &target))
+	require.Equal(t, "user 42 not found", target.Message())
+
+	require.Equal(t, CodeNotFound, CodeOf(err))
+	require.Equal(t, http.StatusNotFound, HTTPStatus(err))
+	require.Equal(t, CodeUnknown, CodeOf(stderrors.New("plain")))
+	require.Equal(t, http.StatusInternalServerError, HTTPStatus(stderrors.New("plain")))
+}
+
+func TestGRPCStatus(t *testing.T) {
+	err := fmt.Errorf("handler: %w", New(CodePermissionDenied, "not an admin"))
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.PermissionDenied, st.Code())
+	require.Equal(t, "handler: not an admin", st.Message())
+}
+
+func TestFields(t *testing.T) {
+	require.Nil(t, Fields(stderrors.New("plain")))
+
+	err := fmt.Errorf("handler: %w", New(CodeAlreadyExists, "email taken").WithMetadata("email", "a@b.c"))
+	require.Equal(t, map[string]interface{}{
+		"error_code":     "already_exists",
+		"http_status":    http.StatusConflict,
+		"grpc_code":      "AlreadyExists",
+		"error_metadata": map[string]interface{}{"email": "a@b.c"},
+	}, Fields(err))
+}
+
+func TestFormat(t *testing.T) {
+	err := Wrap(stderrors.New("no rows"), CodeNotFound, "user not found")
+
+	require.Equal(t, "user not found: no rows", fmt.Sprintf("%v", err))
+	require.Equal(t, `"user not found: no rows"`, fmt.Sprintf("%q", err))
+
+	verbose := fmt.Sprintf("%+v", err)
+	require.Contains(t, verbose, "no rows\nuser not found")
+	require.Contains(t, verbose, "errors.TestFormat")
+}
@@ -0,0 +1,211 @@
+// Package mask hides sensitive keys and values before telemetry leaves the
+// process, it is shared by the jaeger and sentry packages
+package mask
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+const (
+	// Masked replaces every sensitive value
+	Masked   = "***"
+	maxDepth = 32
+)
+
+// Masker hides sensitive keys and values, it is built once per config so the
+// keyword expressions are only compiled when the config changes
+type Masker struct {
+	keywords []string
+	inline   []*regexp.Regexp
+	patterns []*regexp.Regexp
+}
+
+// New masks keys containing any of keywords, the values following them inside
+// strings and every match of patterns
+func New(keywords []string, patterns []*regexp.Regexp) *Masker {
+	m := &Masker{patterns: patterns}
+
+	for _, keyword := range keywords {
+		if keyword == "" {
+			continue
+		}
+
+		m.keywords = append(m.keywords, strings.ToLower(keyword))
+		key := `(?i)([\w\-]*` + regexp.QuoteMeta(keyword) + `[\w\-]*`
+		quoted := `("(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'`
+		m.inline = append(m.inline,
+			// key, optional closing quote, a ":" or "=" separator and then a
+			// quoted or bare value
+			regexp.MustCompile(key+`["']?\s*[:=]\s*)`+quoted+`|[^\s,;&}\])]+)`),
+			// a whitespace separator only counts before a quoted value, so
+			// prose such as "reset password for alice" is left alone
+			regexp.MustCompile(key+`\s+)`+quoted+`)`),
+		)
+	}
+
+	return m
+}
+
+func (m *Masker) Empty() bool {
+	return m == nil || (len(m.keywords) == 0 && len(m.patterns) == 0)
+}
+
+// SensitiveKey reports whether key contains any configured keyword
+func (m *Masker) SensitiveKey(key string) bool {
+	if m == nil {
+		return false
+	}
+
+	key = strings.ToLower(key)
+	for _, keyword := range m.keywords {
+		if strings.Contains(key, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// String masks every keyword/value pair found in s and every match of the
+// configured value patterns
+func (m *Masker) String(s string) string {
+	if m == nil {
+		return s
+	}
+
+	for _, re := range m.inline {
+		s = re.ReplaceAllStringFunc(s, func(match string) string {
+			sub := re.FindStringSubmatch(match)
+			value := Masked
+			if quote := sub[2][0]; quote == '"' || quote == '\'' {
+				value = string(quote) + Masked + string(quote)
+			}
+			return sub[1] + value
+		})
+	}
+
+	return m.Patterns(s)
+}
+
+// Patterns masks the matches of the configured value patterns only, for free
+// text where a keyword is more likely prose than a key
+func (m *Masker) Patterns(s string) string {
+	if m == nil {
+		return s
+	}
+
+	for _, re := range m.patterns {
+		s = re.ReplaceAllString(s, Masked)
+	}
+
+	return s
+}
+
+// Value returns a copy of v where struct fields tagged `mask:"true"`, fields
+// and map keys containing a keyword and string values matching a pattern are
+// masked. Structs and maps become map[string]any and slices become []any so
+// the result can be serialized.
+func (m *Masker) Value(v any) any {
+	if m == nil {
+		m = &Masker{}
+	}
+	return m.value(reflect.ValueOf(v), 0)
+}
+
+// value walks v and returns a copy built from maps, slices and scalars in
+// which sensitive fields, keys and values are replaced
+func (m *Masker) value(v reflect.Value, depth int) any {
+	if !v.IsValid() {
+		return nil
+	}
+	if depth > maxDepth {
+		return Masked
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return m.value(v.Elem(), depth+1)
+
+	case reflect.String:
+		return m.String(v.String())
+
+	case reflect.Struct:
+		return m.structValue(v, depth)
+
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+
+		out := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			if m.SensitiveKey(key) {
+				out[key] = Masked
+				continue
+			}
+			out[key] = m.value(iter.Value(), depth+1)
+		}
+		return out
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice {
+			if v.IsNil() {
+				return nil
+			}
+			if v.Type().Elem().Kind() == reflect.Uint8 {
+				return m.String(string(v.Bytes()))
+			}
+		}
+
+		out := make([]any, v.Len())
+		for i := range v.Len() {
+			out[i] = m.value(v.Index(i), depth+1)
+		}
+		return out
+
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return v.Type().String()
+	}
+
+	return v.Interface()
+}
+
+func (m *Masker) structValue(v reflect.Value, depth int) any {
+	t := v.Type()
+
+	out := make(map[string]any, t.NumField())
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		key := field.Name
+		if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name == "-" {
+			continue
+		} else if name != "" {
+			key = name
+		}
+
+		if field.Tag.Get("mask") == "true" || m.SensitiveKey(key) || m.SensitiveKey(field.Name) {
+			out[key] = Masked
+			continue
+		}
+		out[key] = m.value(v.Field(i), depth+1)
+	}
+
+	// types such as time.Time keep their state private, fall back to the
+	// printed form so they are not rendered as an empty object
+	if len(out) == 0 && t.NumField() > 0 {
+		return m.String(fmt.Sprintf("%+v", v.Interface()))
+	}
+
+	return out
+}
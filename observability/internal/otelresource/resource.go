@@ -0,0 +1,92 @@
+// Package otelresource builds the OpenTelemetry resource describing the
+// service, it is shared by the jaeger and metrics packages so spans and
+// metrics are attributed to the same service
+package otelresource
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// Config describes the service, Version, Environment and InstanceID are
+// reported as service.version, deployment.environment and service.instance.id
+type Config struct {
+	Name        string
+	Version     string
+	Environment string
+	InstanceID  string
+	Attributes  map[string]string
+
+	// Host, Process, OS and Container enable the matching resource detectors
+	Host      bool
+	Process   bool
+	OS        bool
+	Container bool
+}
+
+// New builds the resource of cfg. Options are applied in order so
+// OTEL_RESOURCE_ATTRIBUTES and OTEL_SERVICE_NAME override the configured
+// values at deploy time.
+func New(ctx context.Context, cfg Config) (*resource.Resource, error) {
+	opts := []resource.Option{resource.WithTelemetrySDK()}
+
+	if cfg.Host {
+		opts = append(opts, resource.WithHost(), resource.WithHostID())
+	}
+	if cfg.Process {
+		// command line arguments are left out as they often carry secrets
+		opts = append(opts,
+			resource.WithProcessPID(),
+			resource.WithProcessExecutableName(),
+			resource.WithProcessExecutablePath(),
+			resource.WithProcessOwner(),
+			resource.WithProcessRuntimeName(),
+			resource.WithProcessRuntimeVersion(),
+			resource.WithProcessRuntimeDescription(),
+		)
+	}
+	if cfg.OS {
+		opts = append(opts, resource.WithOS())
+	}
+	if cfg.Container {
+		opts = append(opts, resource.WithContainer())
+	}
+
+	opts = append(opts,
+		resource.WithAttributes(attributes(cfg)...),
+		resource.WithFromEnv(),
+	)
+
+	res, err := resource.New(ctx, opts...)
+	// a detector that finds nothing, e.g. no container, still yields the rest
+	if err != nil && !errors.Is(err, resource.ErrPartialResource) {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func attributes(cfg Config) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, len(cfg.Attributes)+4)
+	for key, value := range cfg.Attributes {
+		attrs = append(attrs, attribute.String(key, value))
+	}
+
+	// the dedicated fields win over the same keys in Attributes
+	attrs = append(attrs, semconv.ServiceName(cfg.Name))
+	if cfg.Version != "" {
+		attrs = append(attrs, semconv.ServiceVersion(cfg.Version))
+	}
+	if cfg.Environment != "" {
+		attrs = append(attrs, semconv.DeploymentEnvironment(cfg.Environment))
+	}
+	if cfg.InstanceID != "" {
+		attrs = append(attrs, semconv.ServiceInstanceID(cfg.InstanceID))
+	}
+
+	return attrs
+}
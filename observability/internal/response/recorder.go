@@ -0,0 +1,56 @@
+// Package response records what a handler wrote to an http.ResponseWriter,
+// it is shared by the jaeger and sentry HTTP middlewares
+package response
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+)
+
+// Recorder captures the status code and body size written through it. It
+// forwards Flush and Hijack, and Unwrap for http.ResponseController, so
+// streaming and websocket handlers keep working behind the middlewares.
+type Recorder struct {
+	http.ResponseWriter
+	Status      int
+	Written     int
+	WroteHeader bool
+}
+
+// NewRecorder wraps w, the status defaults to 200 like net/http
+func NewRecorder(w http.ResponseWriter) *Recorder {
+	return &Recorder{ResponseWriter: w, Status: http.StatusOK}
+}
+
+func (r *Recorder) WriteHeader(status int) {
+	if !r.WroteHeader {
+		r.Status = status
+		r.WroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *Recorder) Write(b []byte) (int, error) {
+	r.WroteHeader = true
+	n, err := r.ResponseWriter.Write(b)
+	r.Written += n
+	return n, err
+}
+
+// Flush sends the buffered data, a no-op when the wrapped writer can't flush
+func (r *Recorder) Flush() {
+	r.WroteHeader = true
+	_ = http.NewResponseController(r.ResponseWriter).Flush()
+}
+
+// Hijack takes over the connection, http.ErrNotSupported when the wrapped
+// writer can't be hijacked
+func (r *Recorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(r.ResponseWriter).Hijack()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (r *Recorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
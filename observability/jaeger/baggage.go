@@ -0,0 +1,82 @@
+package jaeger
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Baggage keys commonly used to filter traces and logs by tenant or user
+const (
+	BaggageTenantID = "tenant.id"
+	BaggageUserID   = "user.id"
+)
+
+// SetBaggage returns a copy of ctx whose baggage holds key with value, the
+// member travels with Inject and every outgoing HTTP or gRPC call
+func SetBaggage(ctx context.Context, key, value string) (context.Context, error) {
+	return SetBaggageValues(ctx, map[string]string{key: value})
+}
+
+// SetBaggageValues is SetBaggage for several members at once, no member is
+// set when any of them is invalid
+func SetBaggageValues(ctx context.Context, values map[string]string) (context.Context, error) {
+	bag := baggage.FromContext(ctx)
+
+	for key, value := range values {
+		member, err := baggage.NewMemberRaw(key, value)
+		if err != nil {
+			return ctx, errors.Wrapf(err, "invalid baggage member %q", key)
+		}
+
+		bag, err = bag.SetMember(member)
+		if err != nil {
+			return ctx, errors.Wrapf(err, "failed to set baggage member %q", key)
+		}
+	}
+
+	return baggage.ContextWithBaggage(ctx, bag), nil
+}
+
+// BaggageValue returns the baggage value stored under key, empty when missing
+func BaggageValue(ctx context.Context, key string) string {
+	return baggage.FromContext(ctx).Member(key).Value()
+}
+
+// BaggageSpanProcessor copies the configured baggage members of the parent
+// context onto every span as attributes when the span starts
+type BaggageSpanProcessor struct {
+	keys []string
+}
+
+// NewBaggageSpanProcessor copies the given baggage keys onto spans, members
+// missing from the context are skipped
+func NewBaggageSpanProcessor(keys ...string) *BaggageSpanProcessor {
+	return &BaggageSpanProcessor{keys: keys}
+}
+
+func (p *BaggageSpanProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	bag := baggage.FromContext(parent)
+	if bag.Len() == 0 {
+		return
+	}
+
+	attrs := make([]attribute.KeyValue, 0, len(p.keys))
+	for _, key := range p.keys {
+		member := bag.Member(key)
+		if member.Key() == "" {
+			continue
+		}
+		attrs = append(attrs, attribute.String(key, member.Value()))
+	}
+	s.SetAttributes(attrs...)
+}
+
+func (p *BaggageSpanProcessor) OnEnd(sdktrace.ReadOnlySpan) {}
+
+func (p *BaggageSpanProcessor) Shutdown(context.Context) error { return nil }
+
+func (p *BaggageSpanProcessor) ForceFlush(context.Context) error { return nil }
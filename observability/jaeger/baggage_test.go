@@ -0,0 +1,65 @@
+package jaeger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSetBaggage(t *testing.T) {
+	ctx, err := SetBaggage(context.Background(), BaggageTenantID, "acme")
+	require.NoError(t, err)
+
+	ctx, err = SetBaggageValues(ctx, map[string]string{BaggageUserID: "42"})
+	require.NoError(t, err)
+
+	require.Equal(t, "acme", BaggageValue(ctx, BaggageTenantID))
+	require.Equal(t, "42", BaggageValue(ctx, BaggageUserID))
+	require.Empty(t, BaggageValue(ctx, "missing"))
+
+	_, err = SetBaggage(ctx, "", "value")
+	require.Error(t, err)
+}
+
+func TestBaggageTravelsWithInject(t *testing.T) {
+	obs, _ := newTestObs(t)
+
+	ctx, err := SetBaggage(context.Background(), BaggageTenantID, "acme")
+	require.NoError(t, err)
+
+	carrier := obs.Inject(ctx)
+	require.Equal(t, "acme", BaggageValue(obs.Extract(context.Background(), carrier), BaggageTenantID))
+}
+
+func TestBaggageSpanProcessor(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(NewBaggageSpanProcessor(BaggageTenantID, BaggageUserID)),
+		sdktrace.WithSyncer(exporter),
+	)
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	ctx, err := SetBaggageValues(context.Background(), map[string]string{
+		BaggageTenantID: "acme",
+		"session":       "secret",
+	})
+	require.NoError(t, err)
+
+	_, span := tp.Tracer("test").Start(ctx, "op")
+	span.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+
+	tenant, ok := spanAttr(spans[0], BaggageTenantID)
+	require.True(t, ok)
+	require.Equal(t, "acme", tenant.AsString())
+
+	_, ok = spanAttr(spans[0], BaggageUserID)
+	require.False(t, ok)
+	_, ok = spanAttr(spans[0], "session")
+	require.False(t, ok)
+}
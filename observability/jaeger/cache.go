@@ -0,0 +1,168 @@
+package jaeger
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/bolanosdev/go-snacks/storage"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CacheOption customizes a TracedCache
+type CacheOption func(*cacheConfig)
+
+type cacheConfig struct {
+	events bool
+	meter  metric.MeterProvider
+}
+
+// WithCacheEvents records every operation as an event on the span of the
+// context instead of starting a child span, for caches hit in hot paths
+func WithCacheEvents() CacheOption {
+	return func(c *cacheConfig) {
+		c.events = true
+	}
+}
+
+// WithCacheMeterProvider records the hit and miss counters on mp instead of
+// the global meter provider
+func WithCacheMeterProvider(mp metric.MeterProvider) CacheOption {
+	return func(c *cacheConfig) {
+		c.meter = mp
+	}
+}
+
+// TracedCache wraps a storage.InMemoryCache, tracing every operation with
+// the cache name, the outcome and a keyed hash of the key. The hash key is
+// random per process, so the collector can correlate operations on the same
+// key within a process but can't recover keys by hashing guesses.
+type TracedCache[K comparable, V any] struct {
+	cache  *storage.InMemoryCache[K, V]
+	name   string
+	tracer JaegerInterface
+	cfg    cacheConfig
+
+	hits   metric.Int64Counter
+	misses metric.Int64Counter
+}
+
+// InstrumentCache wraps cache with tracing and cache.hits / cache.misses
+// counters, name identifies the cache in spans and metrics
+func InstrumentCache[K comparable, V any](tracer JaegerInterface, name string, cache *storage.InMemoryCache[K, V], opts ...CacheOption) *TracedCache[K, V] {
+	cfg := cacheConfig{meter: otel.GetMeterProvider()}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	meter := cfg.meter.Meter(meterName)
+	hits, _ := meter.Int64Counter("cache.hits",
+		metric.WithDescription("Cache lookups that found the key"), metric.WithUnit("{lookup}"))
+	misses, _ := meter.Int64Counter("cache.misses",
+		metric.WithDescription("Cache lookups that missed the key"), metric.WithUnit("{lookup}"))
+
+	return &TracedCache[K, V]{
+		cache:  cache,
+		name:   name,
+		tracer: tracer,
+		cfg:    cfg,
+		hits:   hits,
+		misses: misses,
+	}
+}
+
+// Unwrap returns the underlying cache, operations on it are not traced
+func (c *TracedCache[K, V]) Unwrap() *storage.InMemoryCache[K, V] {
+	return c.cache
+}
+
+func (c *TracedCache[K, V]) Get(ctx context.Context, key K) (V, bool) {
+	finish := c.start(ctx, "get", key)
+	value, found := c.cache.Get(key)
+	finish(&found)
+	return value, found
+}
+
+func (c *TracedCache[K, V]) Set(ctx context.Context, key K, value V) (V, bool) {
+	finish := c.start(ctx, "set", key)
+	value, ok := c.cache.Set(key, value)
+	finish(nil)
+	return value, ok
+}
+
+func (c *TracedCache[K, V]) Pop(ctx context.Context, key K) (V, bool) {
+	finish := c.start(ctx, "pop", key)
+	value, found := c.cache.Pop(key)
+	finish(&found)
+	return value, found
+}
+
+// Remove deletes key, the span records whether the key was present as
+// cache.found, removals are not lookups so they are not counted
+func (c *TracedCache[K, V]) Remove(ctx context.Context, key K) {
+	finish := c.start(ctx, "remove", key)
+	_, found := c.cache.Pop(key)
+	finish(nil, attribute.Bool("cache.found", found))
+}
+
+func (c *TracedCache[K, V]) Has(ctx context.Context, key K) bool {
+	finish := c.start(ctx, "has", key)
+	found := c.cache.Has(key)
+	finish(&found)
+	return found
+}
+
+// start traces op and returns the func recording its outcome, hit is nil
+// for operations that are not lookups and extra adds attributes
+func (c *TracedCache[K, V]) start(ctx context.Context, op string, key K) func(hit *bool, extra ...attribute.KeyValue) {
+	name := attribute.String("cache.name", c.name)
+	attrs := []attribute.KeyValue{
+		name,
+		attribute.String("cache.operation", op),
+		attribute.String("cache.key_hash", hashKey(key)),
+	}
+
+	span := trace.SpanFromContext(ctx)
+	if !c.cfg.events {
+		_, span = c.tracer.Trace(ctx, "cache."+op)
+	}
+
+	return func(hit *bool, extra ...attribute.KeyValue) {
+		attrs = append(attrs, extra...)
+		if hit != nil {
+			attrs = append(attrs, attribute.Bool("cache.hit", *hit))
+			if *hit {
+				c.hits.Add(ctx, 1, metric.WithAttributes(name))
+			} else {
+				c.misses.Add(ctx, 1, metric.WithAttributes(name))
+			}
+		}
+
+		if c.cfg.events {
+			span.AddEvent("cache."+op, trace.WithAttributes(attrs...))
+			return
+		}
+
+		span.SetAttributes(attrs...)
+		span.End()
+	}
+}
+
+// keyHashSecret keys the HMAC of cache keys, it never leaves the process
+var keyHashSecret = func() []byte {
+	secret := make([]byte, 32)
+	_, _ = rand.Read(secret)
+	return secret
+}()
+
+func hashKey(key any) string {
+	h := hmac.New(sha256.New, keyHashSecret)
+	_, _ = fmt.Fprint(h, key)
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
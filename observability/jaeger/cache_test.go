@@ -0,0 +1,111 @@
+package jaeger
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/bolanosdev/go-snacks/storage"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func cacheCounters(t *testing.T, reader *sdkmetric.ManualReader) map[string]int64 {
+	rm := metricdata.ResourceMetrics{}
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	counters := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			for _, point := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				name, _ := point.Attributes.Value("cache.name")
+				require.Equal(t, "users", name.AsString())
+				counters[m.Name] += point.Value
+			}
+		}
+	}
+	return counters
+}
+
+func TestTracedCacheSpans(t *testing.T) {
+	obs, exporter := newTestObs(t)
+	reader := sdkmetric.NewManualReader()
+
+	cache := InstrumentCache(obs, "users", storage.NewInMemoryCache[string, int](),
+		WithCacheMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))))
+
+	ctx, parent := obs.Trace(context.Background(), "handler")
+	cache.Set(ctx, "alice@example.com", 1)
+	value, found := cache.Get(ctx, "alice@example.com")
+	require.True(t, found)
+	require.Equal(t, 1, value)
+	_, found = cache.Get(ctx, "bob@example.com")
+	require.False(t, found)
+	_, found = cache.Pop(ctx, "alice@example.com")
+	require.True(t, found)
+	cache.Remove(ctx, "alice@example.com")
+	parent.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 6)
+
+	names := []string{}
+	hits := []bool{}
+	for _, span := range spans[:5] {
+		names = append(names, span.Name)
+		require.Equal(t, parent.SpanContext().SpanID(), span.Parent.SpanID())
+
+		cacheName, _ := spanAttr(span, "cache.name")
+		require.Equal(t, "users", cacheName.AsString())
+
+		hash, ok := spanAttr(span, "cache.key_hash")
+		require.True(t, ok)
+		require.NotContains(t, hash.AsString(), "@")
+
+		if hit, ok := spanAttr(span, "cache.hit"); ok {
+			hits = append(hits, hit.AsBool())
+		}
+	}
+	require.Equal(t, []string{"cache.set", "cache.get", "cache.get", "cache.pop", "cache.remove"}, names)
+	require.Equal(t, []bool{true, false, true}, hits)
+
+	removed, ok := spanAttr(spans[4], "cache.found")
+	require.True(t, ok)
+	require.False(t, removed.AsBool())
+
+	get, _ := spanAttr(spans[1], "cache.key_hash")
+	set, _ := spanAttr(spans[0], "cache.key_hash")
+	require.Equal(t, set, get)
+
+	// the hash is keyed, an unkeyed digest of a guessed key does not match it
+	guess := sha256.Sum256([]byte("alice@example.com"))
+	require.Len(t, get.AsString(), 16)
+	require.NotEqual(t, hex.EncodeToString(guess[:8]), get.AsString())
+
+	// removals are not lookups
+	require.Equal(t, map[string]int64{"cache.hits": 2, "cache.misses": 1}, cacheCounters(t, reader))
+}
+
+func TestTracedCacheEvents(t *testing.T) {
+	obs, exporter := newTestObs(t)
+
+	cache := InstrumentCache(obs, "users", storage.NewInMemoryCache[string, int](), WithCacheEvents())
+
+	ctx, parent := obs.Trace(context.Background(), "handler")
+	cache.Set(ctx, "alice", 1)
+	require.True(t, cache.Has(ctx, "alice"))
+	parent.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	require.Len(t, spans[0].Events, 2)
+	require.Equal(t, "cache.set", spans[0].Events[0].Name)
+	require.Equal(t, "cache.has", spans[0].Events[1].Name)
+
+	// the wrapped cache is shared with untraced callers
+	value, found := cache.Unwrap().Get("alice")
+	require.True(t, found)
+	require.Equal(t, 1, value)
+}
@@ -0,0 +1,349 @@
+package jaeger
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// UnaryServerInterceptor extracts the caller's trace context from the incoming
+// metadata and wraps every unary call in a server span
+func (t JaegerObs) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, span := t.startServerSpan(ctx, info.FullMethod)
+		defer span.End()
+
+		addMessageEvent(span, semconv.RPCMessageTypeReceived, 1, req)
+
+		res, err := handler(ctx, req)
+		if err == nil {
+			addMessageEvent(span, semconv.RPCMessageTypeSent, 1, res)
+		}
+
+		finishRPCSpan(span, err, serverErrorCode)
+		return res, err
+	}
+}
+
+// StreamServerInterceptor extracts the caller's trace context from the
+// incoming metadata and wraps every streaming call in a server span
+func (t JaegerObs) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, span := t.startServerSpan(ss.Context(), info.FullMethod)
+		defer span.End()
+
+		err := handler(srv, &tracedServerStream{ServerStream: ss, ctx: ctx, span: span})
+
+		finishRPCSpan(span, err, serverErrorCode)
+		return err
+	}
+}
+
+// UnaryClientInterceptor wraps every outgoing unary call in a client span and
+// injects the trace context into the outgoing metadata
+func (t JaegerObs) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, span := t.startClientSpan(ctx, method, cc)
+		defer span.End()
+
+		addMessageEvent(span, semconv.RPCMessageTypeSent, 1, req)
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if err == nil {
+			addMessageEvent(span, semconv.RPCMessageTypeReceived, 1, reply)
+		}
+
+		finishRPCSpan(span, err, clientErrorCode)
+		return err
+	}
+}
+
+// StreamClientInterceptor wraps every outgoing stream in a client span that
+// ends when the stream completes, fails or its context is done
+func (t JaegerObs) StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, span := t.startClientSpan(ctx, method, cc)
+
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			finishRPCSpan(span, err, clientErrorCode)
+			span.End()
+			return cs, err
+		}
+
+		stream := &tracedClientStream{ClientStream: cs, span: span, desc: desc, done: make(chan struct{})}
+		go func() {
+			select {
+			case <-ctx.Done():
+				stream.finish(ctx.Err())
+			case <-stream.done:
+			}
+		}()
+
+		return stream, nil
+	}
+}
+
+func (t JaegerObs) startServerSpan(ctx context.Context, fullMethod string) (context.Context, trace.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = t.propagator().Extract(ctx, metadataCarrier(md))
+
+	attrs := rpcAttributes(fullMethod)
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		if host, port := splitHostPort(p.Addr.String()); host != "" {
+			attrs = append(attrs, semconv.ClientAddress(host))
+			if port > 0 {
+				attrs = append(attrs, semconv.ClientPort(port))
+			}
+		}
+	}
+
+	return t.tp.Tracer(t.cfg.Name).Start(ctx, rpcSpanName(fullMethod),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+// targetHostPort drops the resolver scheme and authority of a gRPC target,
+// dns:///users:50051 is reported as users and 50051. Unix socket targets
+// report the socket path.
+func targetHostPort(target string) (string, int) {
+	if !strings.Contains(target, "://") {
+		return splitHostPort(target)
+	}
+
+	u, err := url.Parse(target)
+	if err != nil {
+		return splitHostPort(target)
+	}
+
+	switch u.Scheme {
+	case "unix", "unix-abstract":
+		return u.Path, 0
+	}
+	return splitHostPort(strings.TrimPrefix(u.Path, "/"))
+}
+
+func (t JaegerObs) startClientSpan(ctx context.Context, fullMethod string, cc *grpc.ClientConn) (context.Context, trace.Span) {
+	attrs := rpcAttributes(fullMethod)
+	if cc != nil {
+		if host, port := targetHostPort(cc.Target()); host != "" {
+			attrs = append(attrs, semconv.ServerAddress(host))
+			if port > 0 {
+				attrs = append(attrs, semconv.ServerPort(port))
+			}
+		}
+	}
+
+	ctx, span := t.tp.Tracer(t.cfg.Name).Start(ctx, rpcSpanName(fullMethod),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	t.propagator().Inject(ctx, metadataCarrier(md))
+
+	return metadata.NewOutgoingContext(ctx, md), span
+}
+
+// tracedServerStream swaps the stream context for the traced one and records
+// a span event per message
+type tracedServerStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	span     trace.Span
+	sent     int
+	received int
+}
+
+func (s *tracedServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *tracedServerStream) SendMsg(m any) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sent++
+		addMessageEvent(s.span, semconv.RPCMessageTypeSent, s.sent, m)
+	}
+	return err
+}
+
+func (s *tracedServerStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.received++
+		addMessageEvent(s.span, semconv.RPCMessageTypeReceived, s.received, m)
+	}
+	return err
+}
+
+// tracedClientStream records a span event per message and ends the span once
+// the server closes the stream
+type tracedClientStream struct {
+	grpc.ClientStream
+	span     trace.Span
+	desc     *grpc.StreamDesc
+	done     chan struct{}
+	once     sync.Once
+	mu       sync.Mutex
+	sent     int
+	received int
+}
+
+func (s *tracedClientStream) SendMsg(m any) error {
+	err := s.ClientStream.SendMsg(m)
+	if err != nil {
+		s.finish(err)
+		return err
+	}
+
+	s.mu.Lock()
+	s.sent++
+	id := s.sent
+	s.mu.Unlock()
+
+	addMessageEvent(s.span, semconv.RPCMessageTypeSent, id, m)
+	return nil
+}
+
+func (s *tracedClientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err == io.EOF {
+		s.finish(nil)
+		return err
+	}
+	if err != nil {
+		s.finish(err)
+		return err
+	}
+
+	s.mu.Lock()
+	s.received++
+	id := s.received
+	s.mu.Unlock()
+
+	addMessageEvent(s.span, semconv.RPCMessageTypeReceived, id, m)
+
+	// without server streaming the first response is also the last one
+	if !s.desc.ServerStreams {
+		s.finish(nil)
+	}
+	return nil
+}
+
+func (s *tracedClientStream) Header() (metadata.MD, error) {
+	md, err := s.ClientStream.Header()
+	if err != nil {
+		s.finish(err)
+	}
+	return md, err
+}
+
+func (s *tracedClientStream) finish(err error) {
+	s.once.Do(func() {
+		finishRPCSpan(s.span, err, clientErrorCode)
+		s.span.End()
+		close(s.done)
+	})
+}
+
+// metadataCarrier adapts grpc metadata to a propagation.TextMapCarrier
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// rpcSpanName turns "/pkg.Service/Method" into "pkg.Service/Method"
+func rpcSpanName(fullMethod string) string {
+	return strings.TrimPrefix(fullMethod, "/")
+}
+
+func rpcAttributes(fullMethod string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{semconv.RPCSystemGRPC}
+
+	service, method, found := strings.Cut(rpcSpanName(fullMethod), "/")
+	if !found {
+		return attrs
+	}
+	if service != "" {
+		attrs = append(attrs, semconv.RPCService(service))
+	}
+	if method != "" {
+		attrs = append(attrs, semconv.RPCMethod(method))
+	}
+
+	return attrs
+}
+
+func addMessageEvent(span trace.Span, messageType attribute.KeyValue, id int, message any) {
+	attrs := []attribute.KeyValue{messageType, semconv.RPCMessageID(id)}
+	if pm, ok := message.(proto.Message); ok {
+		attrs = append(attrs, semconv.RPCMessageUncompressedSize(proto.Size(pm)))
+	}
+
+	span.AddEvent("message", trace.WithAttributes(attrs...))
+}
+
+// finishRPCSpan records the grpc status code and flags the span as failed when
+// isError says the code is an error for this side of the call
+func finishRPCSpan(span trace.Span, err error, isError func(codes.Code) bool) {
+	s, _ := status.FromError(err)
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(s.Code())))
+
+	if err != nil && isError(s.Code()) {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, s.Message())
+	}
+}
+
+// clientErrorCode treats every non OK code as a failure of the call
+func clientErrorCode(code codes.Code) bool {
+	return code != codes.OK
+}
+
+// serverErrorCode only flags codes that point at a server side problem,
+// following the OpenTelemetry rpc conventions
+func serverErrorCode(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented,
+		codes.Internal, codes.Unavailable, codes.DataLoss:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package jaeger
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newHealthClient(t *testing.T, obs JaegerObs) healthpb.HealthClient {
+	listener := bufconn.Listen(1024 * 1024)
+
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(obs.UnaryServerInterceptor()),
+		grpc.StreamInterceptor(obs.StreamServerInterceptor()),
+	)
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	go func() { _ = srv.Serve(listener) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(obs.UnaryClientInterceptor()),
+		grpc.WithStreamInterceptor(obs.StreamClientInterceptor()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestUnaryInterceptorsLinkClientAndServer(t *testing.T) {
+	obs, exporter := newTestObs(t)
+	client := newHealthClient(t, obs)
+
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+
+	server, client_span := spans[0], spans[1]
+	require.Equal(t, "grpc.health.v1.Health/Check", server.Name)
+	require.Equal(t, trace.SpanKindServer, server.SpanKind)
+	require.Equal(t, trace.SpanKindClient, client_span.SpanKind)
+	require.Equal(t, client_span.SpanContext.SpanID(), server.Parent.SpanID())
+
+	method, ok := spanAttr(server, "rpc.method")
+	require.True(t, ok)
+	require.Equal(t, "Check", method.AsString())
+
+	require.Len(t, server.Events, 2)
+	require.Len(t, client_span.Events, 2)
+}
+
+func TestUnaryInterceptorsMapStatusCodes(t *testing.T) {
+	obs, exporter := newTestObs(t)
+	client := newHealthClient(t, obs)
+
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "unknown"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+
+	server, client_span := spans[0], spans[1]
+	require.Equal(t, otelcodes.Unset, server.Status.Code)
+	require.Equal(t, otelcodes.Error, client_span.Status.Code)
+
+	code, ok := spanAttr(server, "rpc.grpc.status_code")
+	require.True(t, ok)
+	require.Equal(t, int64(codes.NotFound), code.AsInt64())
+}
+
+func TestStreamClientInterceptorEndsSpanOnCancel(t *testing.T) {
+	obs, exporter := newTestObs(t)
+	client := newHealthClient(t, obs)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+
+	_, err = stream.Recv()
+	require.NoError(t, err)
+
+	cancel()
+	_, err = stream.Recv()
+	require.Equal(t, codes.Canceled, status.Code(err))
+
+	require.Eventually(t, func() bool {
+		for _, span := range exporter.GetSpans() {
+			if span.SpanKind == trace.SpanKindClient {
+				return span.Status.Code == otelcodes.Error
+			}
+		}
+		return false
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestTargetHostPort(t *testing.T) {
+	for target, want := range map[string]struct {
+		host string
+		port int
+	}{
+		"users:50051":                  {"users", 50051},
+		"dns:///users:50051":           {"users", 50051},
+		"dns://8.8.8.8/users:50051":    {"users", 50051},
+		"dns:///[::1]:50051":           {"::1", 50051},
+		"passthrough:///bufnet":        {"bufnet", 0},
+		"unix:///var/run/users.sock":   {"/var/run/users.sock", 0},
+		"xds:///users.mesh.local:8080": {"users.mesh.local", 8080},
+	} {
+		host, port := targetHostPort(target)
+		require.Equal(t, want.host, host, target)
+		require.Equal(t, want.port, port, target)
+	}
+}
@@ -0,0 +1,224 @@
+package jaeger
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+const meterName = "github.com/bolanosdev/go-snacks/observability/jaeger"
+
+// ExporterState describes whether spans currently reach the collector
+type ExporterState string
+
+const (
+	// ExporterStateUninitialized is reported before Initialize succeeded
+	ExporterStateUninitialized ExporterState = "uninitialized"
+	// ExporterStateExternal is reported when the provider came from
+	// WithTracerProvider, its owner is responsible for exporting
+	ExporterStateExternal   ExporterState = "external"
+	ExporterStateConnecting ExporterState = "connecting"
+	ExporterStateReady      ExporterState = "ready"
+	// ExporterStateFailing is reported when the collector is unreachable or
+	// the last export failed
+	ExporterStateFailing  ExporterState = "failing"
+	ExporterStateShutdown ExporterState = "shutdown"
+)
+
+// HealthStatus is the exporter state reported by Health
+type HealthStatus struct {
+	State ExporterState `json:"state"`
+	// LastError is the last export error, cleared by a successful export
+	LastError   error     `json:"-"`
+	LastErrorAt time.Time `json:"last_error_at,omitzero"`
+	// LastExportAt is when spans were last exported successfully
+	LastExportAt time.Time `json:"last_export_at,omitzero"`
+	// Exported, Failed and SampledOut count spans handed to the collector,
+	// spans whose export failed and spans tail sampling chose not to keep.
+	// Spans lost before reaching the exporter are not counted.
+	Exported   int64 `json:"exported"`
+	Failed     int64 `json:"failed"`
+	SampledOut int64 `json:"sampled_out"`
+}
+
+// Healthy reports whether spans are expected to reach the collector
+func (s HealthStatus) Healthy() bool {
+	return s.State == ExporterStateReady || s.State == ExporterStateExternal
+}
+
+func (s HealthStatus) MarshalJSON() ([]byte, error) {
+	type status HealthStatus
+
+	lastError := ""
+	if s.LastError != nil {
+		lastError = s.LastError.Error()
+	}
+
+	return json.Marshal(struct {
+		status
+		Healthy   bool   `json:"healthy"`
+		LastError string `json:"last_error,omitempty"`
+	}{status(s), s.Healthy(), lastError})
+}
+
+// exporterHealth tracks the collector connection and export outcomes, it is
+// shared by every copy of the JaegerObs created by Initialize
+type exporterHealth struct {
+	conn *grpc.ClientConn
+
+	exported   atomic.Int64
+	failed     atomic.Int64
+	sampledOut atomic.Int64
+	shutdown   atomic.Bool
+
+	mu           sync.Mutex
+	lastErr      error
+	lastErrAt    time.Time
+	lastExportAt time.Time
+
+	exportedCounter   metric.Int64Counter
+	failedCounter     metric.Int64Counter
+	sampledOutCounter metric.Int64Counter
+}
+
+// newExporterHealth registers the span counters on the global meter
+// provider, see the metrics package
+func newExporterHealth(conn *grpc.ClientConn) *exporterHealth {
+	meter := otel.GetMeterProvider().Meter(meterName)
+
+	h := &exporterHealth{conn: conn}
+	h.exportedCounter, _ = meter.Int64Counter("jaeger.spans.exported",
+		metric.WithDescription("Spans exported to the collector"), metric.WithUnit("{span}"))
+	h.failedCounter, _ = meter.Int64Counter("jaeger.spans.failed",
+		metric.WithDescription("Spans whose export to the collector failed"), metric.WithUnit("{span}"))
+	h.sampledOutCounter, _ = meter.Int64Counter("jaeger.spans.sampled_out",
+		metric.WithDescription("Spans tail sampling chose not to export"), metric.WithUnit("{span}"))
+
+	return h
+}
+
+func (h *exporterHealth) recordExport(ctx context.Context, spans int, err error) {
+	now := time.Now()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err != nil {
+		h.failed.Add(int64(spans))
+		h.failedCounter.Add(ctx, int64(spans))
+		h.lastErr = err
+		h.lastErrAt = now
+		return
+	}
+
+	h.exported.Add(int64(spans))
+	h.exportedCounter.Add(ctx, int64(spans))
+	h.lastErr = nil
+	h.lastExportAt = now
+}
+
+func (h *exporterHealth) recordSampledOut(spans int) {
+	h.sampledOut.Add(int64(spans))
+	h.sampledOutCounter.Add(context.Background(), int64(spans))
+}
+
+func (h *exporterHealth) status(ctx context.Context) HealthStatus {
+	h.mu.Lock()
+	status := HealthStatus{
+		LastError:    h.lastErr,
+		LastErrorAt:  h.lastErrAt,
+		LastExportAt: h.lastExportAt,
+		Exported:     h.exported.Load(),
+		Failed:       h.failed.Load(),
+		SampledOut:   h.sampledOut.Load(),
+	}
+	h.mu.Unlock()
+
+	switch {
+	case h.shutdown.Load():
+		status.State = ExporterStateShutdown
+	case status.LastError != nil:
+		status.State = ExporterStateFailing
+	default:
+		status.State = h.connState(ctx)
+	}
+
+	return status
+}
+
+// connState waits for the collector connection to settle until ctx is done
+func (h *exporterHealth) connState(ctx context.Context) ExporterState {
+	state := h.conn.GetState()
+	if state == connectivity.Idle {
+		h.conn.Connect()
+	}
+
+	for state != connectivity.Ready && state != connectivity.TransientFailure && state != connectivity.Shutdown {
+		if !h.conn.WaitForStateChange(ctx, state) {
+			break
+		}
+		state = h.conn.GetState()
+	}
+
+	switch state {
+	case connectivity.Ready:
+		return ExporterStateReady
+	case connectivity.TransientFailure:
+		return ExporterStateFailing
+	case connectivity.Shutdown:
+		return ExporterStateShutdown
+	default:
+		return ExporterStateConnecting
+	}
+}
+
+// healthExporter records the outcome of every export
+type healthExporter struct {
+	sdktrace.SpanExporter
+	health *exporterHealth
+}
+
+func (e healthExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	e.health.recordExport(ctx, len(spans), err)
+	return err
+}
+
+// Health reports the exporter state, the last export error and the span
+// counters. A collector connection still being established is waited for
+// until ctx is done, use a short timeout.
+func (t JaegerObs) Health(ctx context.Context) HealthStatus {
+	if t.external {
+		return HealthStatus{State: ExporterStateExternal}
+	}
+	if t.health == nil {
+		return HealthStatus{State: ExporterStateUninitialized}
+	}
+	return t.health.status(ctx)
+}
+
+// HealthHandler is a readiness probe answering 200 when spans reach the
+// collector and 503 otherwise, with the HealthStatus as JSON body
+func (t JaegerObs) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), time.Second)
+		defer cancel()
+
+		status := t.Health(ctx)
+
+		w.Header().Set("Content-Type", "application/json")
+		if !status.Healthy() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(status)
+	})
+}
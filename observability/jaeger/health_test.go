@@ -0,0 +1,127 @@
+package jaeger
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+// newCollector starts a gRPC server without the trace service, connections
+// succeed but every export fails with Unimplemented
+func newCollector(t *testing.T) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	srv := grpc.NewServer()
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	return lis.Addr().String()
+}
+
+func TestHealthReportsExportFailures(t *testing.T) {
+	tracer, err := NewJaegerObs(context.Background()).
+		WithConfig(JaegerConfig{Name: "svc", Hostname: newCollector(t)}).
+		Initialize()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = tracer.Shutdown(context.Background()) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	status := tracer.Health(ctx)
+	require.Equal(t, ExporterStateReady, status.State)
+	require.True(t, status.Healthy())
+
+	_, span := tracer.Trace(context.Background(), "op")
+	span.End()
+
+	status = tracer.Health(ctx)
+	require.Equal(t, ExporterStateFailing, status.State)
+	require.Error(t, status.LastError)
+	require.False(t, status.LastErrorAt.IsZero())
+	require.Equal(t, int64(1), status.Failed)
+	require.Zero(t, status.Exported)
+
+	rec := httptest.NewRecorder()
+	tracer.HealthHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	body := map[string]any{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Equal(t, "failing", body["state"])
+	require.Equal(t, false, body["healthy"])
+	require.NotEmpty(t, body["last_error"])
+	require.Equal(t, float64(1), body["failed"])
+
+	require.NoError(t, tracer.Shutdown(context.Background()))
+	require.Equal(t, ExporterStateShutdown, tracer.Health(ctx).State)
+	require.Equal(t, connectivity.Shutdown, tracer.(JaegerObs).conn.GetState())
+}
+
+func TestHealthUnreachableCollector(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := lis.Addr().String()
+	require.NoError(t, lis.Close())
+
+	tracer, err := NewJaegerObs(context.Background()).
+		WithConfig(JaegerConfig{Name: "svc", Hostname: addr}).
+		Initialize()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = tracer.Shutdown(context.Background()) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	status := tracer.Health(ctx)
+	require.False(t, status.Healthy())
+	require.Contains(t, []ExporterState{ExporterStateConnecting, ExporterStateFailing}, status.State)
+}
+
+func TestHealthWithoutExporter(t *testing.T) {
+	obs, _ := newTestObs(t)
+	require.Equal(t, ExporterStateExternal, obs.Health(context.Background()).State)
+
+	rec := httptest.NewRecorder()
+	obs.HealthHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	status := NewJaegerObs(context.Background()).Health(context.Background())
+	require.Equal(t, ExporterStateUninitialized, status.State)
+	require.False(t, status.Healthy())
+}
+
+func TestHealthCountsExportedAndSampledOutSpans(t *testing.T) {
+	health := newExporterHealth(nil)
+
+	processor := NewTailSamplingProcessor(
+		sdktrace.NewSimpleSpanProcessor(healthExporter{SpanExporter: tracetest.NewInMemoryExporter(), health: health}),
+		TailSamplingConfig{SampleRatio: 0, LatencyThreshold: time.Second},
+	)
+	processor.sampledOut = health.recordSampledOut
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(processor))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	start := time.Now()
+	_, fast := tp.Tracer("test").Start(context.Background(), "fast", trace.WithTimestamp(start))
+	fast.End(trace.WithTimestamp(start.Add(time.Millisecond)))
+	_, slow := tp.Tracer("test").Start(context.Background(), "slow", trace.WithTimestamp(start))
+	slow.End(trace.WithTimestamp(start.Add(2 * time.Second)))
+
+	require.Equal(t, int64(1), health.exported.Load())
+	require.Equal(t, int64(1), health.sampledOut.Load())
+	require.Zero(t, health.failed.Load())
+}
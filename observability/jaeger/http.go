@@ -0,0 +1,186 @@
+package jaeger
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bolanosdev/go-snacks/observability/internal/response"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HTTPMiddleware returns net/http middleware that extracts the incoming trace
+// context and wraps every request in a server span.
+// The span is named after route, when route is empty the pattern matched by
+// http.ServeMux is used and the method alone as a last resort.
+func (t JaegerObs) HTTPMiddleware(route string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := t.propagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+			ctx, span := t.tp.Tracer(t.cfg.Name).Start(ctx, httpSpanName(r.Method, route),
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(httpServerAttributes(r)...),
+			)
+			defer span.End()
+
+			rw := response.NewRecorder(w)
+			req := r.WithContext(ctx)
+			next.ServeHTTP(rw, req)
+
+			// ServeMux records the matched pattern on the request it was handed,
+			// route is shared by every request so it is copied first
+			spanRoute := route
+			if spanRoute == "" && req.Pattern != "" {
+				spanRoute = patternRoute(req.Pattern)
+				span.SetName(httpSpanName(r.Method, spanRoute))
+			}
+			if spanRoute != "" {
+				span.SetAttributes(semconv.HTTPRoute(spanRoute))
+			}
+
+			span.SetAttributes(
+				semconv.HTTPResponseStatusCode(rw.Status),
+				semconv.HTTPResponseBodySize(rw.Written),
+			)
+
+			// server spans only flag 5xx as errors, 4xx are the client's fault
+			if rw.Status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(rw.Status))
+			}
+		})
+	}
+}
+
+// HTTPTransport wraps base so every outgoing request runs inside a client span
+// and carries the trace context headers. A nil base uses http.DefaultTransport.
+func (t JaegerObs) HTTPTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &tracingTransport{base: base, obs: t}
+}
+
+type tracingTransport struct {
+	base http.RoundTripper
+	obs  JaegerObs
+}
+
+func (rt *tracingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	ctx, span := rt.obs.tp.Tracer(rt.obs.cfg.Name).Start(r.Context(), httpSpanName(r.Method, ""),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(httpClientAttributes(r)...),
+	)
+	defer span.End()
+
+	// a RoundTripper must not modify the caller's request
+	r = r.Clone(ctx)
+	rt.obs.propagator().Inject(ctx, propagation.HeaderCarrier(r.Header))
+
+	res, err := rt.base.RoundTrip(r)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return res, err
+	}
+
+	span.SetAttributes(semconv.HTTPResponseStatusCode(res.StatusCode))
+	if res.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(res.StatusCode))
+	}
+
+	return res, nil
+}
+
+func httpSpanName(method, route string) string {
+	if route == "" {
+		return method
+	}
+	return fmt.Sprintf("%s %s", method, route)
+}
+
+// patternRoute drops the optional method prefix of a ServeMux pattern
+func patternRoute(pattern string) string {
+	if _, path, found := strings.Cut(pattern, " "); found {
+		return strings.TrimSpace(path)
+	}
+	return pattern
+}
+
+func httpServerAttributes(r *http.Request) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String(r.Method),
+		semconv.URLPath(r.URL.Path),
+		semconv.URLScheme(requestScheme(r)),
+		semconv.NetworkProtocolVersion(fmt.Sprintf("%d.%d", r.ProtoMajor, r.ProtoMinor)),
+	}
+
+	if host, port := splitHostPort(r.Host); host != "" {
+		attrs = append(attrs, semconv.ServerAddress(host))
+		if port > 0 {
+			attrs = append(attrs, semconv.ServerPort(port))
+		}
+	}
+	if host, port := splitHostPort(r.RemoteAddr); host != "" {
+		attrs = append(attrs, semconv.ClientAddress(host))
+		if port > 0 {
+			attrs = append(attrs, semconv.ClientPort(port))
+		}
+	}
+	if ua := r.UserAgent(); ua != "" {
+		attrs = append(attrs, semconv.UserAgentOriginal(ua))
+	}
+	if r.URL.RawQuery != "" {
+		attrs = append(attrs, semconv.URLQuery(r.URL.RawQuery))
+	}
+
+	return attrs
+}
+
+func httpClientAttributes(r *http.Request) []attribute.KeyValue {
+	// strip credentials so they never reach the span
+	u := *r.URL
+	u.User = nil
+
+	attrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String(r.Method),
+		semconv.URLFull(u.String()),
+	}
+
+	if host, port := splitHostPort(r.URL.Host); host != "" {
+		attrs = append(attrs, semconv.ServerAddress(host))
+		if port > 0 {
+			attrs = append(attrs, semconv.ServerPort(port))
+		}
+	}
+
+	return attrs
+}
+
+func requestScheme(r *http.Request) string {
+	if r.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+func splitHostPort(hostport string) (string, int) {
+	host, portStr, err := net.SplitHostPort(hostport)
+	if err != nil {
+		return hostport, 0
+	}
+
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return host, 0
+	}
+
+	return host, port
+}
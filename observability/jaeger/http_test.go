@@ -0,0 +1,153 @@
+package jaeger
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newTestObs(t *testing.T) (JaegerObs, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	obs := NewJaegerObs(context.Background(),
+		WithTracerProvider(tp),
+		WithPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})),
+	)
+	return obs, exporter
+}
+
+func spanAttr(span tracetest.SpanStub, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestHTTPMiddlewareUsesMuxPattern(t *testing.T) {
+	obs, exporter := newTestObs(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		require.True(t, trace.SpanContextFromContext(r.Context()).IsValid())
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	srv := httptest.NewServer(obs.HTTPMiddleware("")(mux))
+	defer srv.Close()
+
+	res, err := http.Get(srv.URL + "/users/42")
+	require.NoError(t, err)
+	res.Body.Close()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	require.Equal(t, "GET /users/{id}", spans[0].Name)
+	require.Equal(t, trace.SpanKindServer, spans[0].SpanKind)
+	require.Equal(t, codes.Error, spans[0].Status.Code)
+
+	status, ok := spanAttr(spans[0], "http.response.status_code")
+	require.True(t, ok)
+	require.Equal(t, int64(500), status.AsInt64())
+}
+
+func TestHTTPMiddlewareRoutePerRequest(t *testing.T) {
+	obs, exporter := newTestObs(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("GET /orders/{id}", func(w http.ResponseWriter, r *http.Request) {})
+	handler := obs.HTTPMiddleware("")(mux)
+
+	for _, path := range []string{"/users/1", "/orders/1", "/nothing"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 3)
+	require.Equal(t, "GET /users/{id}", spans[0].Name)
+	require.Equal(t, "GET /orders/{id}", spans[1].Name)
+	require.Equal(t, "GET", spans[2].Name)
+
+	_, ok := spanAttr(spans[2], "http.route")
+	require.False(t, ok)
+}
+
+func TestHTTPMiddlewareKeepsFlusherAndHijacker(t *testing.T) {
+	obs, exporter := newTestObs(t)
+
+	handler := obs.HTTPMiddleware("/stream")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		require.True(t, ok)
+		_, err := w.Write([]byte("chunk"))
+		require.NoError(t, err)
+		flusher.Flush()
+
+		_, ok = w.(http.Hijacker)
+		require.True(t, ok)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stream", nil))
+	require.True(t, rec.Flushed)
+	require.Len(t, exporter.GetSpans(), 1)
+
+	server := httptest.NewServer(obs.HTTPMiddleware("/ws")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		require.NoError(t, err)
+		defer conn.Close()
+		_, _ = buf.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 8\r\nConnection: close\r\n\r\nhijacked")
+		_ = buf.Flush()
+	})))
+	defer server.Close()
+
+	res, err := http.Get(server.URL)
+	require.NoError(t, err)
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+	require.Equal(t, "hijacked", string(body))
+}
+
+func TestHTTPTransportPropagatesContext(t *testing.T) {
+	obs, exporter := newTestObs(t)
+
+	srv := httptest.NewServer(obs.HTTPMiddleware("/ping")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})))
+	defer srv.Close()
+
+	client := &http.Client{Transport: obs.HTTPTransport(nil)}
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/ping", nil)
+	require.NoError(t, err)
+
+	res, err := client.Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+
+	require.Empty(t, req.Header.Get("traceparent"))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+
+	server, client_span := spans[0], spans[1]
+	require.Equal(t, "GET /ping", server.Name)
+	require.Equal(t, codes.Unset, server.Status.Code)
+	require.Equal(t, trace.SpanKindClient, client_span.SpanKind)
+	require.Equal(t, codes.Error, client_span.Status.Code)
+	require.Equal(t, client_span.SpanContext.TraceID(), server.SpanContext.TraceID())
+	require.Equal(t, client_span.SpanContext.SpanID(), server.Parent.SpanID())
+}
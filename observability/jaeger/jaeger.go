@@ -2,7 +2,9 @@ package jaeger
 
 import (
 	"context"
-	"fmt"
+	"database/sql/driver"
+	"net/http"
+	"regexp"
 	"runtime"
 	"strings"
 	"time"
@@ -11,12 +13,10 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/propagation"
-	"go.opentelemetry.io/otel/sdk/resource"
 	"go.opentelemetry.io/otel/trace"
 
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
-	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -26,6 +26,36 @@ type JaegerConfig struct {
 	Name              string
 	Hostname          string
 	SensitiveKeywords []string
+	// SensitivePatterns mask any attribute value they match, see CardNumberPattern and EmailPattern
+	SensitivePatterns []*regexp.Regexp
+	// ServiceVersion, Environment and InstanceID are reported as service.version,
+	// deployment.environment and service.instance.id
+	ServiceVersion string
+	Environment    string
+	InstanceID     string
+	// ResourceAttributes are added to the resource of every span
+	ResourceAttributes map[string]string
+	Detectors          ResourceDetectors
+	// Propagators selects the trace context formats read from and written to
+	// requests, defaults to W3C TraceContext and Baggage
+	Propagators []Propagator
+	// TextMapPropagators are combined with Propagators, e.g.
+	// sentry.SentryObs.Propagator
+	TextMapPropagators []propagation.TextMapPropagator
+	// RegisterGlobal makes Initialize install the tracer provider and
+	// propagator as the otel globals, otherwise they stay scoped to the
+	// returned JaegerObs
+	RegisterGlobal bool
+	// TailSampling buffers spans per trace and keeps failed or slow traces
+	// plus a ratio of the others, every span is exported when nil
+	TailSampling *TailSamplingConfig
+	// BaggageKeys are copied from the baggage onto every span as attributes,
+	// see BaggageTenantID and BaggageUserID
+	BaggageKeys []string
+	// SpanProcessors see every span started by the provider created by
+	// Initialize, ahead of tail sampling and export, e.g.
+	// sentry.SentryObs.SpanProcessor
+	SpanProcessors []sdktrace.SpanProcessor
 }
 
 // JaegerInterface defines the interface for tracing operations
@@ -35,42 +65,114 @@ type JaegerInterface interface {
 	Trace(c context.Context, name string) (context.Context, trace.Span)
 	TraceFunc(c context.Context) context.Context
 	TraceDB(c context.Context, query string, args interface{}) context.Context
+	HTTPMiddleware(route string) func(http.Handler) http.Handler
+	HTTPTransport(base http.RoundTripper) http.RoundTripper
+	UnaryServerInterceptor() grpc.UnaryServerInterceptor
+	StreamServerInterceptor() grpc.StreamServerInterceptor
+	UnaryClientInterceptor() grpc.UnaryClientInterceptor
+	StreamClientInterceptor() grpc.StreamClientInterceptor
+	WrapDriver(d driver.Driver, system string) driver.Driver
+	WrapConnector(c driver.Connector, system string) driver.Connector
+	Inject(c context.Context) map[string]string
+	Extract(c context.Context, carrier map[string]string) context.Context
+	TraceProducer(c context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span)
+	TraceConsumer(c context.Context, name string, carrier map[string]string, attrs ...attribute.KeyValue) (context.Context, trace.Span)
+	TracerProvider() trace.TracerProvider
+	Propagator() propagation.TextMapPropagator
+	Health(c context.Context) HealthStatus
+	HealthHandler() http.Handler
+	Shutdown(c context.Context) error
 }
 
 type JaegerObs struct {
-	cfg JaegerConfig
-	ctx context.Context
-	tp  trace.TracerProvider
+	cfg  JaegerConfig
+	ctx  context.Context
+	tp   trace.TracerProvider
+	prop propagation.TextMapPropagator
+	mask *masker
+	// sdk is the provider created by Initialize, nil when tp was supplied
+	sdk *sdktrace.TracerProvider
+	// health tracks the exporter created by Initialize
+	health *exporterHealth
+	// conn is the collector connection created by Initialize, the exporter
+	// does not own it so Shutdown closes it
+	conn *grpc.ClientConn
+	// external is set when tp was supplied through WithTracerProvider
+	external bool
 }
 
-func NewJaegerObs(ctx context.Context) JaegerObs {
+// Option customizes the JaegerObs built by NewJaegerObs
+type Option func(*JaegerObs)
+
+// WithTracerProvider makes the JaegerObs start its spans from an existing
+// provider instead of the global one, Initialize then skips creating its own
+// exporter and provider
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(t *JaegerObs) {
+		t.tp = tp
+		t.external = true
+	}
+}
+
+// WithPropagator makes the JaegerObs inject and extract trace context with p
+// instead of the global propagator
+func WithPropagator(p propagation.TextMapPropagator) Option {
+	return func(t *JaegerObs) {
+		t.prop = p
+	}
+}
+
+func NewJaegerObs(ctx context.Context, opts ...Option) JaegerObs {
 	tp := otel.GetTracerProvider()
-	return JaegerObs{
+	t := JaegerObs{
 		cfg: JaegerConfig{
 			Name:              "tracer",
 			Hostname:          "",
 			SensitiveKeywords: []string{},
 		},
-		ctx: ctx,
-		tp:  tp,
+		ctx:  ctx,
+		tp:   tp,
+		mask: &masker{},
+	}
+
+	for _, opt := range opts {
+		opt(&t)
 	}
+
+	return t
 }
 
 func (t JaegerObs) WithConfig(cfg JaegerConfig) JaegerInterface {
 	t.cfg = cfg
+	t.mask = newMasker(cfg)
 	return t
 }
 
 func (t JaegerObs) Initialize() (JaegerInterface, error) {
+	if t.prop == nil {
+		propagator, err := newPropagator(t.cfg.Propagators)
+		if err != nil {
+			return t, errors.Wrap(err, "failed to create propagator for jaeger")
+		}
+		if len(t.cfg.TextMapPropagators) > 0 {
+			propagators := append([]propagation.TextMapPropagator{propagator}, t.cfg.TextMapPropagators...)
+			propagator = propagation.NewCompositeTextMapPropagator(propagators...)
+		}
+		t.prop = propagator
+	}
+
+	// an existing provider already knows where to export
+	if t.external {
+		t.register()
+		return t, nil
+	}
+
 	// dont register trace provider if JAEGER information isnt provided through app.yaml
 	if t.cfg.Hostname == "" {
 		return t, errors.New("missing jaeger dial hostname")
 	}
 
-	res, err := resource.New(t.ctx, resource.WithAttributes(
-		semconv.ServiceName(t.cfg.Name),
-	))
-
+	res, err := t.buildResource(t.ctx)
 	if err != nil {
 		return t, errors.Wrap(err, "failed to create resource for jaeger")
 	}
@@ -87,23 +189,86 @@ func (t JaegerObs) Initialize() (JaegerInterface, error) {
 
 	exporter, err := otlptracegrpc.New(ctx, otlptracegrpc.WithGRPCConn(conn), otlptracegrpc.WithTimeout(1000*time.Millisecond))
 	if err != nil {
+		conn.Close()
 		return t, errors.Wrap(err, "failed to create exporter for jaeger")
 	}
 
-	processor := sdktrace.NewSimpleSpanProcessor(exporter)
+	health := newExporterHealth(conn)
 
-	tp := sdktrace.NewTracerProvider(
+	spanExporter := newMaskingExporter(healthExporter{SpanExporter: exporter, health: health}, t.mask)
+
+	processor := sdktrace.NewSimpleSpanProcessor(spanExporter)
+	if t.cfg.TailSampling != nil {
+		sampler := NewTailSamplingProcessor(sdktrace.NewBatchSpanProcessor(spanExporter), *t.cfg.TailSampling)
+		sampler.sampledOut = health.recordSampledOut
+		processor = sampler
+	}
+
+	opts := []sdktrace.TracerProviderOption{
 		sdktrace.WithSampler(sdktrace.AlwaysSample()),
 		sdktrace.WithResource(res),
-		sdktrace.WithSpanProcessor(processor),
-	)
+	}
+	if len(t.cfg.BaggageKeys) > 0 {
+		// registered first so the attributes are set before the span is exported
+		opts = append(opts, sdktrace.WithSpanProcessor(NewBaggageSpanProcessor(t.cfg.BaggageKeys...)))
+	}
+	for _, sp := range t.cfg.SpanProcessors {
+		opts = append(opts, sdktrace.WithSpanProcessor(sp))
+	}
+	opts = append(opts, sdktrace.WithSpanProcessor(processor))
+
+	tp := sdktrace.NewTracerProvider(opts...)
 
-	otel.SetTracerProvider(tp)
-	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	t.sdk = tp
+	t.tp = tp
+	t.health = health
+	t.conn = conn
+	t.register()
 
 	return t, nil
 }
 
+// register installs the provider and propagator as the otel globals when
+// JaegerConfig.RegisterGlobal is set
+func (t JaegerObs) register() {
+	if !t.cfg.RegisterGlobal {
+		return
+	}
+
+	otel.SetTracerProvider(t.tp)
+	otel.SetTextMapPropagator(t.prop)
+}
+
+// TracerProvider returns the provider spans are started from, handy for
+// third party instrumentation
+func (t JaegerObs) TracerProvider() trace.TracerProvider {
+	return t.tp
+}
+
+// Propagator returns the propagator used to inject and extract trace context
+func (t JaegerObs) Propagator() propagation.TextMapPropagator {
+	return t.propagator()
+}
+
+// Shutdown flushes and stops the provider created by Initialize, a provider
+// supplied through WithTracerProvider is left to its owner
+func (t JaegerObs) Shutdown(ctx context.Context) error {
+	if t.sdk == nil {
+		return nil
+	}
+	if t.health != nil {
+		defer t.health.shutdown.Store(true)
+	}
+
+	err := t.sdk.Shutdown(ctx)
+	if t.conn != nil {
+		if closeErr := t.conn.Close(); closeErr != nil && err == nil {
+			err = errors.Wrap(closeErr, "failed to close jaeger connection")
+		}
+	}
+	return err
+}
+
 func (t JaegerObs) TraceFunc(ctx context.Context) context.Context {
 	pc, _, _, _ := runtime.Caller(1)
 
@@ -143,10 +308,8 @@ func (t JaegerObs) TraceDB(ctx context.Context, query string, args interface{})
 	)
 
 	if args != nil {
-		argsStr := fmt.Sprintf("%+v", args)
-		maskedArgs := t.MaskSensitiveData(argsStr)
 		span.SetAttributes(
-			attribute.String("db.args", maskedArgs),
+			attribute.String("db.args", t.maskArgs(args)),
 		)
 	}
 
@@ -159,31 +322,9 @@ func (t JaegerObs) Trace(c context.Context, name string) (context.Context, trace
 	return ctx, span
 }
 
-func (t JaegerObs) MaskSensitiveData(argsStr string) string {
-	masked := argsStr
-
-	for _, keyword := range t.cfg.SensitiveKeywords {
-		if strings.Contains(strings.ToLower(masked), strings.ToLower(keyword)) {
-			lowerMasked := strings.ToLower(masked)
-			idx := strings.Index(lowerMasked, strings.ToLower(keyword))
-
-			if idx != -1 {
-				valueStart := idx + len(keyword)
-				for valueStart < len(masked) && (masked[valueStart] == ':' || masked[valueStart] == ' ') {
-					valueStart++
-				}
-
-				valueEnd := valueStart
-				for valueEnd < len(masked) && masked[valueEnd] != ' ' && masked[valueEnd] != ',' && masked[valueEnd] != '}' && masked[valueEnd] != ']' {
-					valueEnd++
-				}
-
-				if valueEnd > valueStart {
-					masked = masked[:valueStart] + "***" + masked[valueEnd:]
-				}
-			}
-		}
+func (t JaegerObs) propagator() propagation.TextMapPropagator {
+	if t.prop != nil {
+		return t.prop
 	}
-
-	return masked
+	return otel.GetTextMapPropagator()
 }
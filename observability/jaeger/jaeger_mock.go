@@ -2,8 +2,14 @@ package jaeger
 
 import (
 	"context"
+	"database/sql/driver"
+	"net/http"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"google.golang.org/grpc"
 )
 
 type MockTracer struct {
@@ -35,3 +41,86 @@ func (m MockTracer) TraceFunc(c context.Context) context.Context {
 func (m MockTracer) TraceDB(c context.Context, query string, args interface{}) context.Context {
 	return c
 }
+
+func (m MockTracer) HTTPMiddleware(route string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return next
+	}
+}
+
+func (m MockTracer) HTTPTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		return http.DefaultTransport
+	}
+	return base
+}
+
+func (m MockTracer) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(ctx, req)
+	}
+}
+
+func (m MockTracer) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, ss)
+	}
+}
+
+func (m MockTracer) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+func (m MockTracer) StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
+
+func (m MockTracer) WrapDriver(d driver.Driver, system string) driver.Driver {
+	return d
+}
+
+func (m MockTracer) WrapConnector(c driver.Connector, system string) driver.Connector {
+	return c
+}
+
+func (m MockTracer) Inject(c context.Context) map[string]string {
+	return map[string]string{}
+}
+
+func (m MockTracer) Extract(c context.Context, carrier map[string]string) context.Context {
+	return c
+}
+
+func (m MockTracer) TraceProducer(c context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return c, trace.SpanFromContext(c)
+}
+
+func (m MockTracer) TraceConsumer(c context.Context, name string, carrier map[string]string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return c, trace.SpanFromContext(c)
+}
+
+func (m MockTracer) TracerProvider() trace.TracerProvider {
+	return noop.NewTracerProvider()
+}
+
+func (m MockTracer) Propagator() propagation.TextMapPropagator {
+	return propagation.NewCompositeTextMapPropagator()
+}
+
+func (m MockTracer) Health(c context.Context) HealthStatus {
+	return HealthStatus{State: ExporterStateReady}
+}
+
+func (m MockTracer) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func (m MockTracer) Shutdown(c context.Context) error {
+	return nil
+}
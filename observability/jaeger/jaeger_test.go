@@ -0,0 +1,98 @@
+package jaeger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestInitializeKeepsProviderScoped(t *testing.T) {
+	global := otel.GetTracerProvider()
+
+	tracer, err := NewJaegerObs(context.Background()).
+		WithConfig(JaegerConfig{Name: "svc", Hostname: newCollector(t)}).
+		Initialize()
+	require.NoError(t, err)
+	defer tracer.Shutdown(context.Background())
+
+	require.IsType(t, &sdktrace.TracerProvider{}, tracer.TracerProvider())
+	require.Equal(t, global, otel.GetTracerProvider())
+
+	_, span := tracer.Trace(context.Background(), "scoped")
+	defer span.End()
+	require.True(t, span.SpanContext().IsValid())
+}
+
+func TestInitializeSpanProcessorsAndPropagators(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+
+	tracer, err := NewJaegerObs(context.Background()).
+		WithConfig(JaegerConfig{
+			Name:               "svc",
+			Hostname:           newCollector(t),
+			SpanProcessors:     []sdktrace.SpanProcessor{recorder},
+			TextMapPropagators: []propagation.TextMapPropagator{propagation.Baggage{}},
+			Propagators:        []Propagator{PropagatorB3},
+		}).
+		Initialize()
+	require.NoError(t, err)
+	defer tracer.Shutdown(context.Background())
+
+	_, span := tracer.Trace(context.Background(), "processed")
+	defer span.End()
+
+	require.Len(t, recorder.Started(), 1)
+	require.Equal(t, "processed", recorder.Started()[0].Name())
+	require.ElementsMatch(t, []string{"b3", "baggage"}, tracer.Propagator().Fields())
+}
+
+func TestInitializeWithExistingProvider(t *testing.T) {
+	tp := sdktrace.NewTracerProvider()
+	defer tp.Shutdown(context.Background())
+
+	tracer, err := NewJaegerObs(context.Background(), WithTracerProvider(tp)).
+		WithConfig(JaegerConfig{Name: "svc", Propagators: []Propagator{PropagatorB3}}).
+		Initialize()
+	require.NoError(t, err)
+
+	require.Same(t, tp, tracer.TracerProvider())
+	require.Equal(t, []string{"b3"}, tracer.Propagator().Fields())
+
+	// the provider belongs to the caller, Shutdown must leave it running
+	require.NoError(t, tracer.Shutdown(context.Background()))
+	_, span := tracer.Trace(context.Background(), "still running")
+	require.True(t, span.SpanContext().IsValid())
+	span.End()
+}
+
+func TestInitializeRegisterGlobal(t *testing.T) {
+	global := otel.GetTracerProvider()
+	globalPropagator := otel.GetTextMapPropagator()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(global)
+		otel.SetTextMapPropagator(globalPropagator)
+	})
+
+	tp := sdktrace.NewTracerProvider()
+	defer tp.Shutdown(context.Background())
+
+	tracer, err := NewJaegerObs(context.Background(), WithTracerProvider(tp)).
+		WithConfig(JaegerConfig{Name: "svc", RegisterGlobal: true}).
+		Initialize()
+	require.NoError(t, err)
+
+	require.Same(t, tp, otel.GetTracerProvider())
+	require.Equal(t, tracer.Propagator(), otel.GetTextMapPropagator())
+}
+
+func TestInitializeRequiresHostname(t *testing.T) {
+	_, err := NewJaegerObs(context.Background()).
+		WithConfig(JaegerConfig{Name: "svc"}).
+		Initialize()
+	require.Error(t, err)
+}
@@ -0,0 +1,205 @@
+// Package jaegertest provides an in-memory span recorder implementing
+// jaeger.JaegerInterface so tests can assert on the spans their code creates.
+package jaegertest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bolanosdev/go-snacks/observability/jaeger"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// Recorder is a jaeger.JaegerInterface backed by a real SDK tracer provider
+// that keeps every ended span in memory
+type Recorder struct {
+	jaeger.JaegerObs
+
+	tb       testing.TB
+	exporter *tracetest.InMemoryExporter
+	provider *sdktrace.TracerProvider
+}
+
+// NewRecorder creates a Recorder whose provider is shut down when the test ends
+func NewRecorder(tb testing.TB) *Recorder {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithSyncer(exporter),
+	)
+	tb.Cleanup(func() {
+		_ = provider.Shutdown(context.Background())
+	})
+
+	return &Recorder{
+		JaegerObs: jaeger.NewJaegerObs(context.Background(),
+			jaeger.WithTracerProvider(provider),
+			// the default propagators of Initialize, which the recorder never runs
+			jaeger.WithPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})),
+		),
+		tb:       tb,
+		exporter: exporter,
+		provider: provider,
+	}
+}
+
+func (r *Recorder) WithConfig(cfg jaeger.JaegerConfig) jaeger.JaegerInterface {
+	r.JaegerObs = r.JaegerObs.WithConfig(cfg).(jaeger.JaegerObs)
+	return r
+}
+
+// Initialize never dials a collector, spans only go to the in-memory exporter
+func (r *Recorder) Initialize() (jaeger.JaegerInterface, error) {
+	return r, nil
+}
+
+// Provider returns the SDK tracer provider spans are recorded from
+func (r *Recorder) Provider() *sdktrace.TracerProvider {
+	return r.provider
+}
+
+// Spans returns every span ended so far, in the order they ended
+func (r *Recorder) Spans() tracetest.SpanStubs {
+	return r.exporter.GetSpans()
+}
+
+// Reset drops the recorded spans
+func (r *Recorder) Reset() {
+	r.exporter.Reset()
+}
+
+// FindSpan returns the first ended span called name
+func (r *Recorder) FindSpan(name string) (tracetest.SpanStub, bool) {
+	for _, span := range r.exporter.GetSpans() {
+		if span.Name == name {
+			return span, true
+		}
+	}
+	return tracetest.SpanStub{}, false
+}
+
+// RequireSpan returns the span called name and stops the test when it was
+// never recorded
+func (r *Recorder) RequireSpan(name string) tracetest.SpanStub {
+	r.tb.Helper()
+
+	span, ok := r.FindSpan(name)
+	if !ok {
+		r.tb.Fatalf("span %q was not recorded, got %v", name, r.spanNames())
+	}
+	return span
+}
+
+// AssertAttribute checks that the span called name carries the attribute kv
+func (r *Recorder) AssertAttribute(name string, kv attribute.KeyValue) bool {
+	r.tb.Helper()
+
+	span, ok := r.FindSpan(name)
+	if !ok {
+		r.tb.Errorf("span %q was not recorded, got %v", name, r.spanNames())
+		return false
+	}
+
+	for _, attr := range span.Attributes {
+		if attr.Key != kv.Key {
+			continue
+		}
+		if attr.Value != kv.Value {
+			r.tb.Errorf("span %q attribute %q: expected %v, got %v", name, kv.Key, kv.Value.Emit(), attr.Value.Emit())
+			return false
+		}
+		return true
+	}
+
+	r.tb.Errorf("span %q has no attribute %q", name, kv.Key)
+	return false
+}
+
+// AssertNoAttribute checks that the span called name does not carry key
+func (r *Recorder) AssertNoAttribute(name string, key attribute.Key) bool {
+	r.tb.Helper()
+
+	span, ok := r.FindSpan(name)
+	if !ok {
+		r.tb.Errorf("span %q was not recorded, got %v", name, r.spanNames())
+		return false
+	}
+
+	for _, attr := range span.Attributes {
+		if attr.Key == key {
+			r.tb.Errorf("span %q: unexpected attribute %q = %v", name, key, attr.Value.Emit())
+			return false
+		}
+	}
+	return true
+}
+
+// AssertStatus checks the status code of the span called name
+func (r *Recorder) AssertStatus(name string, code codes.Code) bool {
+	r.tb.Helper()
+
+	span, ok := r.FindSpan(name)
+	if !ok {
+		r.tb.Errorf("span %q was not recorded, got %v", name, r.spanNames())
+		return false
+	}
+
+	if span.Status.Code != code {
+		r.tb.Errorf("span %q status: expected %v, got %v (%s)", name, code, span.Status.Code, span.Status.Description)
+		return false
+	}
+	return true
+}
+
+// AssertChildOf checks that the span called child was started directly from
+// the span called parent
+func (r *Recorder) AssertChildOf(child, parent string) bool {
+	r.tb.Helper()
+
+	c, ok := r.FindSpan(child)
+	if !ok {
+		r.tb.Errorf("span %q was not recorded, got %v", child, r.spanNames())
+		return false
+	}
+	p, ok := r.FindSpan(parent)
+	if !ok {
+		r.tb.Errorf("span %q was not recorded, got %v", parent, r.spanNames())
+		return false
+	}
+
+	if c.Parent.SpanID() != p.SpanContext.SpanID() || c.Parent.TraceID() != p.SpanContext.TraceID() {
+		r.tb.Errorf("span %q is not a child of %q", child, parent)
+		return false
+	}
+	return true
+}
+
+// AssertRoot checks that the span called name has no parent
+func (r *Recorder) AssertRoot(name string) bool {
+	r.tb.Helper()
+
+	span, ok := r.FindSpan(name)
+	if !ok {
+		r.tb.Errorf("span %q was not recorded, got %v", name, r.spanNames())
+		return false
+	}
+
+	if span.Parent.IsValid() {
+		r.tb.Errorf("span %q has parent %s", name, span.Parent.SpanID())
+		return false
+	}
+	return true
+}
+
+func (r *Recorder) spanNames() []string {
+	spans := r.exporter.GetSpans()
+	names := make([]string, len(spans))
+	for i, span := range spans {
+		names[i] = span.Name
+	}
+	return names
+}
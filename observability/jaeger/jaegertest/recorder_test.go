@@ -0,0 +1,93 @@
+package jaegertest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bolanosdev/go-snacks/observability/jaeger"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// failureTB counts reported failures instead of failing the running test
+type failureTB struct {
+	testing.TB
+	failures int
+}
+
+func (f *failureTB) Helper() {}
+
+func (f *failureTB) Errorf(format string, args ...any) {
+	f.failures++
+}
+
+func TestRecorderImplementsJaegerInterface(t *testing.T) {
+	var tracer jaeger.JaegerInterface = NewRecorder(t)
+
+	tracer, err := tracer.WithConfig(jaeger.JaegerConfig{Name: "svc"}).Initialize()
+	require.NoError(t, err)
+	require.IsType(t, &Recorder{}, tracer)
+}
+
+func TestRecorderInjectExtractRoundTrip(t *testing.T) {
+	recorder := NewRecorder(t)
+
+	ctx, err := jaeger.SetBaggage(context.Background(), "tenant.id", "acme")
+	require.NoError(t, err)
+	ctx, producer := recorder.Trace(ctx, "producer")
+	carrier := recorder.Inject(ctx)
+	producer.End()
+
+	require.Contains(t, carrier, "traceparent")
+	require.Equal(t, "tenant.id=acme", carrier["baggage"])
+
+	consumer := recorder.Extract(context.Background(), carrier)
+	_, span := recorder.Trace(consumer, "consumer")
+	span.End()
+
+	recorder.AssertChildOf("consumer", "producer")
+	require.Equal(t, "acme", jaeger.BaggageValue(consumer, "tenant.id"))
+}
+
+func TestRecorderRecordsSpans(t *testing.T) {
+	recorder := NewRecorder(t)
+
+	ctx, parent := recorder.Trace(context.Background(), "parent")
+	_, child := recorder.Trace(ctx, "child")
+	child.SetAttributes(attribute.String("user.id", "42"))
+	child.SetStatus(codes.Error, "boom")
+	child.End()
+	parent.End()
+
+	require.Len(t, recorder.Spans(), 2)
+	recorder.RequireSpan("child")
+	recorder.AssertAttribute("child", attribute.String("user.id", "42"))
+	recorder.AssertNoAttribute("parent", "user.id")
+	recorder.AssertStatus("child", codes.Error)
+	recorder.AssertStatus("parent", codes.Unset)
+	recorder.AssertChildOf("child", "parent")
+	recorder.AssertRoot("parent")
+
+	_, ok := recorder.FindSpan("missing")
+	require.False(t, ok)
+
+	recorder.Reset()
+	require.Empty(t, recorder.Spans())
+}
+
+func TestRecorderAssertionsReportFailures(t *testing.T) {
+	recorder := NewRecorder(t)
+
+	_, span := recorder.Trace(context.Background(), "span")
+	span.SetAttributes(attribute.Int("count", 1))
+	span.End()
+
+	inner := &failureTB{TB: t}
+	probe := &Recorder{JaegerObs: recorder.JaegerObs, tb: inner, exporter: recorder.exporter, provider: recorder.provider}
+
+	require.False(t, probe.AssertAttribute("span", attribute.Int("count", 2)))
+	require.False(t, probe.AssertStatus("span", codes.Error))
+	require.False(t, probe.AssertChildOf("span", "missing"))
+	require.Equal(t, 3, inner.failures)
+}
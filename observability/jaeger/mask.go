@@ -0,0 +1,141 @@
+package jaeger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/bolanosdev/go-snacks/observability/internal/mask"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+var (
+	// CardNumberPattern matches 13 to 19 digit card numbers, optionally grouped by spaces or dashes
+	CardNumberPattern = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+	// EmailPattern matches email addresses
+	EmailPattern = regexp.MustCompile(`[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}`)
+)
+
+// masker adds the span attribute helpers to mask.Masker
+type masker struct {
+	*mask.Masker
+}
+
+func newMasker(cfg JaegerConfig) *masker {
+	return &masker{Masker: mask.New(cfg.SensitiveKeywords, cfg.SensitivePatterns)}
+}
+
+func (m *masker) empty() bool {
+	return m == nil || m.Masker.Empty()
+}
+
+func (m *masker) maskAttributes(attrs []attribute.KeyValue) []attribute.KeyValue {
+	masked := make([]attribute.KeyValue, len(attrs))
+	for i, kv := range attrs {
+		masked[i] = m.maskAttribute(kv)
+	}
+	return masked
+}
+
+func (m *masker) maskAttribute(kv attribute.KeyValue) attribute.KeyValue {
+	if m.SensitiveKey(string(kv.Key)) {
+		return kv.Key.String(mask.Masked)
+	}
+
+	switch kv.Value.Type() {
+	case attribute.STRING:
+		return kv.Key.String(m.String(kv.Value.AsString()))
+	case attribute.STRINGSLICE:
+		values := kv.Value.AsStringSlice()
+		for i, value := range values {
+			values[i] = m.String(value)
+		}
+		return kv.Key.StringSlice(values)
+	}
+
+	return kv
+}
+
+// MaskSensitiveData masks the value following every occurrence of a sensitive
+// keyword in argsStr, plus any match of the configured sensitive patterns
+func (t JaegerObs) MaskSensitiveData(argsStr string) string {
+	if t.mask.empty() {
+		return argsStr
+	}
+	return t.mask.String(argsStr)
+}
+
+// MaskValue returns a copy of v where struct fields tagged `mask:"true"`,
+// fields and map keys containing a sensitive keyword and string values
+// matching a sensitive pattern are masked. Structs and maps become
+// map[string]any and slices become []any so the result can be serialized.
+func (t JaegerObs) MaskValue(v any) any {
+	if t.mask == nil {
+		return mask.New(nil, nil).Value(v)
+	}
+	return t.mask.Value(v)
+}
+
+// maskArgs renders args as JSON after masking, falling back to the printed
+// form for values json cannot encode
+func (t JaegerObs) maskArgs(args any) string {
+	masked := t.MaskValue(args)
+
+	encoded, err := json.Marshal(masked)
+	if err != nil {
+		return t.MaskSensitiveData(fmt.Sprintf("%+v", masked))
+	}
+	return string(encoded)
+}
+
+// NewMaskingExporter wraps exp so span and event attributes are masked with
+// the SensitiveKeywords and SensitivePatterns of cfg right before export.
+// Initialize installs it on its own exporter, callers passing their tracer
+// provider through WithTracerProvider wrap theirs with it. exp is returned
+// as is when cfg has nothing to mask.
+func NewMaskingExporter(exp sdktrace.SpanExporter, cfg JaegerConfig) sdktrace.SpanExporter {
+	return newMaskingExporter(exp, newMasker(cfg))
+}
+
+func newMaskingExporter(exp sdktrace.SpanExporter, m *masker) sdktrace.SpanExporter {
+	if m.empty() {
+		return exp
+	}
+	return maskingExporter{SpanExporter: exp, mask: m}
+}
+
+// maskingExporter masks span and event attributes right before export so
+// every attribute is covered no matter where it was set
+type maskingExporter struct {
+	sdktrace.SpanExporter
+	mask *masker
+}
+
+func (e maskingExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	masked := make([]sdktrace.ReadOnlySpan, len(spans))
+	for i, span := range spans {
+		masked[i] = maskedSpan{ReadOnlySpan: span, mask: e.mask}
+	}
+	return e.SpanExporter.ExportSpans(ctx, masked)
+}
+
+type maskedSpan struct {
+	sdktrace.ReadOnlySpan
+	mask *masker
+}
+
+func (s maskedSpan) Attributes() []attribute.KeyValue {
+	return s.mask.maskAttributes(s.ReadOnlySpan.Attributes())
+}
+
+func (s maskedSpan) Events() []sdktrace.Event {
+	events := s.ReadOnlySpan.Events()
+	masked := make([]sdktrace.Event, len(events))
+	for i, event := range events {
+		event.Attributes = s.mask.maskAttributes(event.Attributes)
+		masked[i] = event
+	}
+	return masked
+}
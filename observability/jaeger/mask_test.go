@@ -0,0 +1,157 @@
+package jaeger
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newMaskingObs() JaegerObs {
+	return NewJaegerObs(context.Background()).WithConfig(JaegerConfig{
+		Name:              "test",
+		SensitiveKeywords: []string{"password", "token"},
+		SensitivePatterns: []*regexp.Regexp{CardNumberPattern, EmailPattern},
+	}).(JaegerObs)
+}
+
+func TestMaskSensitiveDataMasksEveryOccurrence(t *testing.T) {
+	obs := newMaskingObs()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "printed map",
+			input:    "map[password:secret token:abc user:john]",
+			expected: "map[password:*** token:*** user:john]",
+		},
+		{
+			name:     "quoted value with spaces",
+			input:    `password: "a b" user: john`,
+			expected: `password: "***" user: john`,
+		},
+		{
+			name:     "repeated keyword",
+			input:    "password=one, old_password=two",
+			expected: "password=***, old_password=***",
+		},
+		{
+			name:     "json",
+			input:    `{"password":"a \"b\"","nested":{"accessToken":"xyz"}}`,
+			expected: `{"password":"***","nested":{"accessToken":"***"}}`,
+		},
+		{
+			name:     "quoted value after whitespace",
+			input:    `set password "a b" for john`,
+			expected: `set password "***" for john`,
+		},
+		{
+			name:     "prose",
+			input:    "reset password for alice, token expired",
+			expected: "reset password for alice, token expired",
+		},
+		{
+			name:     "patterns",
+			input:    "card 4111 1111 1111 1111 from john@example.com",
+			expected: "card *** from ***",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, obs.MaskSensitiveData(tt.input))
+		})
+	}
+}
+
+func TestMaskValueWalksStructures(t *testing.T) {
+	type credentials struct {
+		User     string `json:"user"`
+		Password string `json:"password"`
+		PIN      int    `mask:"true"`
+		internal string
+	}
+
+	obs := newMaskingObs()
+
+	masked := obs.MaskValue([]any{
+		credentials{User: "john", Password: "a b", PIN: 1234, internal: "x"},
+		map[string]any{
+			"profile": map[string]string{"email": "john@example.com", "refresh_token": "abc"},
+		},
+		&credentials{User: "jane"},
+	})
+
+	require.Equal(t, []any{
+		map[string]any{"user": "john", "password": "***", "PIN": "***"},
+		map[string]any{
+			"profile": map[string]any{"email": "***", "refresh_token": "***"},
+		},
+		map[string]any{"user": "jane", "password": "***", "PIN": "***"},
+	}, masked)
+}
+
+func TestMaskingExporterMasksAllAttributes(t *testing.T) {
+	cfg := JaegerConfig{
+		Name:              "test",
+		SensitiveKeywords: []string{"password", "token"},
+		SensitivePatterns: []*regexp.Regexp{CardNumberPattern, EmailPattern},
+	}
+
+	// a caller owned provider only gets masked through NewMaskingExporter
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(NewMaskingExporter(exporter, cfg)))
+	defer tp.Shutdown(context.Background())
+	obs := NewJaegerObs(context.Background(), WithTracerProvider(tp)).WithConfig(cfg).(JaegerObs)
+
+	_, span := obs.Trace(context.Background(), "login")
+	span.SetAttributes(
+		attribute.String("user.email", "john@example.com"),
+		attribute.String("auth.token", "abc"),
+		attribute.Int("attempts", 3),
+	)
+	span.AddEvent("retry", trace.WithAttributes(attribute.String("detail", "password=hunter2")))
+	span.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+
+	email, _ := spanAttr(spans[0], "user.email")
+	token, _ := spanAttr(spans[0], "auth.token")
+	attempts, _ := spanAttr(spans[0], "attempts")
+	require.Equal(t, "***", email.AsString())
+	require.Equal(t, "***", token.AsString())
+	require.Equal(t, int64(3), attempts.AsInt64())
+	require.Equal(t, "password=***", spans[0].Events[0].Attributes[0].Value.AsString())
+
+	require.Same(t, exporter, NewMaskingExporter(exporter, JaegerConfig{}))
+}
+
+func TestTraceDBMasksArgs(t *testing.T) {
+	obs := newMaskingObs()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer tp.Shutdown(context.Background())
+	obs.tp = tp
+
+	obs.TraceDB(context.Background(), "UPDATE users SET password = $1 WHERE email = $2", []any{"a b", "john@example.com"})
+	obs.TraceDB(context.Background(), "SELECT 1", map[string]any{"Password": "a b", "id": 7})
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+
+	args, _ := spanAttr(spans[0], "db.args")
+	require.Equal(t, `["a b","***"]`, args.AsString())
+
+	args, _ = spanAttr(spans[1], "db.args")
+	require.Equal(t, `{"Password":"***","id":7}`, args.AsString())
+}
@@ -0,0 +1,51 @@
+package jaeger
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Inject serializes the trace context and baggage of ctx into a map that can
+// travel with a queued message or job payload
+func (t JaegerObs) Inject(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	t.propagator().Inject(ctx, carrier)
+	return carrier
+}
+
+// Extract restores the trace context and baggage written by Inject, spans
+// started from the returned context become children of the remote span
+func (t JaegerObs) Extract(ctx context.Context, carrier map[string]string) context.Context {
+	return t.propagator().Extract(ctx, propagation.MapCarrier(carrier))
+}
+
+// TraceProducer starts a producer span for a message about to be enqueued,
+// call Inject with the returned context to build the message carrier
+func (t JaegerObs) TraceProducer(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return t.tp.Tracer(t.cfg.Name).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+// TraceConsumer starts a consumer span for a message carrying carrier. The
+// span starts a new trace linked to the producer instead of joining it, the
+// job may run long after the originating request finished. The baggage of
+// the carrier is kept on the returned context.
+func (t JaegerObs) TraceConsumer(ctx context.Context, name string, carrier map[string]string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	remote := t.Extract(ctx, carrier)
+
+	opts := []trace.SpanStartOption{
+		trace.WithNewRoot(),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(attrs...),
+	}
+	if sc := trace.SpanContextFromContext(remote); sc.IsValid() {
+		opts = append(opts, trace.WithLinks(trace.Link{SpanContext: sc}))
+	}
+
+	return t.tp.Tracer(t.cfg.Name).Start(remote, name, opts...)
+}
@@ -0,0 +1,64 @@
+package jaeger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestInjectExtractRoundTrip(t *testing.T) {
+	obs, _ := newTestObs(t)
+
+	member, err := baggage.NewMember("tenant.id", "acme")
+	require.NoError(t, err)
+	bag, err := baggage.New(member)
+	require.NoError(t, err)
+
+	ctx, span := obs.Trace(baggage.ContextWithBaggage(context.Background(), bag), "request")
+	defer span.End()
+
+	carrier := obs.Inject(ctx)
+	require.Contains(t, carrier, "traceparent")
+	require.Contains(t, carrier, "baggage")
+
+	restored := obs.Extract(context.Background(), carrier)
+	require.Equal(t, span.SpanContext().TraceID(), trace.SpanContextFromContext(restored).TraceID())
+	require.Equal(t, "acme", baggage.FromContext(restored).Member("tenant.id").Value())
+}
+
+func TestTraceConsumerLinksProducer(t *testing.T) {
+	obs, exporter := newTestObs(t)
+
+	ctx, producer := obs.TraceProducer(context.Background(), "emails publish", attribute.String("messaging.destination.name", "emails"))
+	carrier := obs.Inject(ctx)
+	producer.End()
+
+	_, consumer := obs.TraceConsumer(context.Background(), "emails process", carrier)
+	consumer.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+
+	published, processed := spans[0], spans[1]
+	require.Equal(t, trace.SpanKindProducer, published.SpanKind)
+	require.Equal(t, trace.SpanKindConsumer, processed.SpanKind)
+	require.False(t, processed.Parent.IsValid())
+	require.NotEqual(t, published.SpanContext.TraceID(), processed.SpanContext.TraceID())
+	require.Len(t, processed.Links, 1)
+	require.Equal(t, published.SpanContext.SpanID(), processed.Links[0].SpanContext.SpanID())
+}
+
+func TestTraceConsumerWithoutCarrier(t *testing.T) {
+	obs, exporter := newTestObs(t)
+
+	_, consumer := obs.TraceConsumer(context.Background(), "cron", nil)
+	consumer.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	require.Empty(t, spans[0].Links)
+}
@@ -0,0 +1,56 @@
+package jaeger
+
+import (
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/contrib/propagators/b3"
+	jaegerprop "go.opentelemetry.io/contrib/propagators/jaeger"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Propagator names a trace context format, the values match the ones used by
+// the OTEL_PROPAGATORS environment variable
+type Propagator string
+
+const (
+	// PropagatorTraceContext is the W3C traceparent/tracestate format
+	PropagatorTraceContext Propagator = "tracecontext"
+	// PropagatorBaggage is the W3C baggage format
+	PropagatorBaggage Propagator = "baggage"
+	// PropagatorB3 is the single "b3" header Zipkin format
+	PropagatorB3 Propagator = "b3"
+	// PropagatorB3Multi is the multi "X-B3-*" headers Zipkin format
+	PropagatorB3Multi Propagator = "b3multi"
+	// PropagatorJaeger is the "uber-trace-id" Jaeger format
+	PropagatorJaeger Propagator = "jaeger"
+)
+
+// defaultPropagators are used when JaegerConfig.Propagators is empty
+var defaultPropagators = []Propagator{PropagatorTraceContext, PropagatorBaggage}
+
+// newPropagator combines the named formats, every format is extracted from
+// incoming requests and injected into outgoing ones
+func newPropagator(names []Propagator) (propagation.TextMapPropagator, error) {
+	if len(names) == 0 {
+		names = defaultPropagators
+	}
+
+	propagators := make([]propagation.TextMapPropagator, 0, len(names))
+	for _, name := range names {
+		switch name {
+		case PropagatorTraceContext:
+			propagators = append(propagators, propagation.TraceContext{})
+		case PropagatorBaggage:
+			propagators = append(propagators, propagation.Baggage{})
+		case PropagatorB3:
+			propagators = append(propagators, b3.New(b3.WithInjectEncoding(b3.B3SingleHeader)))
+		case PropagatorB3Multi:
+			propagators = append(propagators, b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader)))
+		case PropagatorJaeger:
+			propagators = append(propagators, jaegerprop.Jaeger{})
+		default:
+			return nil, errors.Errorf("unknown propagator %q", name)
+		}
+	}
+
+	return propagation.NewCompositeTextMapPropagator(propagators...), nil
+}
@@ -0,0 +1,51 @@
+package jaeger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNewPropagatorDefaults(t *testing.T) {
+	propagator, err := newPropagator(nil)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"traceparent", "tracestate", "baggage"}, propagator.Fields())
+}
+
+func TestNewPropagatorUnknown(t *testing.T) {
+	_, err := newPropagator([]Propagator{"xray"})
+	require.Error(t, err)
+}
+
+func TestNewPropagatorMixedFleet(t *testing.T) {
+	propagator, err := newPropagator([]Propagator{PropagatorTraceContext, PropagatorB3Multi, PropagatorJaeger})
+	require.NoError(t, err)
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+
+	incoming := []propagation.MapCarrier{
+		{"uber-trace-id": "4bf92f3577b34da6a3ce929d0e0e4736:00f067aa0ba902b7:0:1"},
+		{"x-b3-traceid": "4bf92f3577b34da6a3ce929d0e0e4736", "x-b3-spanid": "00f067aa0ba902b7", "x-b3-sampled": "1"},
+		{"b3": "4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-1"},
+	}
+
+	for _, carrier := range incoming {
+		sc := trace.SpanContextFromContext(propagator.Extract(context.Background(), carrier))
+		require.Equal(t, traceID, sc.TraceID())
+		require.Equal(t, spanID, sc.SpanID())
+		require.True(t, sc.IsSampled())
+	}
+
+	ctx := propagator.Extract(context.Background(), incoming[0])
+	outgoing := propagation.MapCarrier{}
+	propagator.Inject(ctx, outgoing)
+
+	require.Contains(t, outgoing, "traceparent")
+	require.Contains(t, outgoing, "x-b3-traceid")
+	require.Contains(t, outgoing, "uber-trace-id")
+	require.NotContains(t, outgoing, "b3")
+}
@@ -0,0 +1,37 @@
+package jaeger
+
+import (
+	"context"
+
+	"github.com/bolanosdev/go-snacks/observability/internal/otelresource"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// ResourceDetectors selects the optional resource detectors run by Initialize
+type ResourceDetectors struct {
+	// Host adds host.name and host.id
+	Host bool
+	// Process adds the pid, executable, owner and go runtime. Command line
+	// arguments are left out as they often carry secrets.
+	Process bool
+	// OS adds os.type and os.description
+	OS bool
+	// Container adds container.id when running inside a container
+	Container bool
+}
+
+// buildResource describes the service every span is attributed to, the
+// metrics package builds the same resource
+func (t JaegerObs) buildResource(ctx context.Context) (*resource.Resource, error) {
+	return otelresource.New(ctx, otelresource.Config{
+		Name:        t.cfg.Name,
+		Version:     t.cfg.ServiceVersion,
+		Environment: t.cfg.Environment,
+		InstanceID:  t.cfg.InstanceID,
+		Attributes:  t.cfg.ResourceAttributes,
+		Host:        t.cfg.Detectors.Host,
+		Process:     t.cfg.Detectors.Process,
+		OS:          t.cfg.Detectors.OS,
+		Container:   t.cfg.Detectors.Container,
+	})
+}
@@ -0,0 +1,66 @@
+package jaeger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func resourceValue(t *testing.T, obs JaegerObs, key attribute.Key) (string, bool) {
+	res, err := obs.buildResource(context.Background())
+	require.NoError(t, err)
+
+	value, ok := res.Set().Value(key)
+	return value.Emit(), ok
+}
+
+func TestBuildResourceFromConfig(t *testing.T) {
+	obs := NewJaegerObs(context.Background()).WithConfig(JaegerConfig{
+		Name:               "users",
+		ServiceVersion:     "1.4.2",
+		Environment:        "staging",
+		InstanceID:         "users-7f9c",
+		ResourceAttributes: map[string]string{"team": "identity", "service.name": "ignored"},
+		Detectors:          ResourceDetectors{Host: true, Process: true, OS: true},
+	}).(JaegerObs)
+
+	expected := map[attribute.Key]string{
+		"service.name":           "users",
+		"service.version":        "1.4.2",
+		"deployment.environment": "staging",
+		"service.instance.id":    "users-7f9c",
+		"team":                   "identity",
+	}
+	for key, want := range expected {
+		got, ok := resourceValue(t, obs, key)
+		require.True(t, ok, key)
+		require.Equal(t, want, got, key)
+	}
+
+	for _, key := range []attribute.Key{"host.name", "process.pid", "os.type"} {
+		_, ok := resourceValue(t, obs, key)
+		require.True(t, ok, key)
+	}
+
+	_, ok := resourceValue(t, obs, "process.command_args")
+	require.False(t, ok)
+}
+
+func TestBuildResourceHonorsEnvironment(t *testing.T) {
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "deployment.environment=prod,region=eu-west-1")
+
+	obs := NewJaegerObs(context.Background()).WithConfig(JaegerConfig{
+		Name:        "users",
+		Environment: "staging",
+	}).(JaegerObs)
+
+	env, _ := resourceValue(t, obs, "deployment.environment")
+	region, _ := resourceValue(t, obs, "region")
+	_, hasHost := resourceValue(t, obs, "host.name")
+
+	require.Equal(t, "prod", env)
+	require.Equal(t, "eu-west-1", region)
+	require.False(t, hasHost)
+}
@@ -0,0 +1,87 @@
+package jaeger
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RunOption customizes the span started by Run and RunValue
+type RunOption func(*runConfig)
+
+type runConfig struct {
+	attrs []attribute.KeyValue
+}
+
+// WithAttributes sets attrs on the span before fn runs
+func WithAttributes(attrs ...attribute.KeyValue) RunOption {
+	return func(cfg *runConfig) {
+		cfg.attrs = append(cfg.attrs, attrs...)
+	}
+}
+
+// Run executes fn inside a span called name. An error returned by fn is
+// recorded on the span and marks it as failed, a panic is recorded as an
+// exception event with its stack trace before being re-raised.
+//
+//	err := jaeger.Run(ctx, tracer, "UserService.Delete", func(ctx context.Context) error {
+//		return repo.Delete(ctx, id)
+//	}, jaeger.WithAttributes(attribute.Int("user.id", id)))
+func Run(ctx context.Context, tracer JaegerInterface, name string, fn func(context.Context) error, opts ...RunOption) error {
+	_, err := RunValue(ctx, tracer, name, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	}, opts...)
+	return err
+}
+
+// RunValue is Run for functions that also return a value
+//
+//	user, err := jaeger.RunValue(ctx, tracer, "UserService.Get", func(ctx context.Context) (*User, error) {
+//		return repo.Get(ctx, id)
+//	})
+func RunValue[T any](ctx context.Context, tracer JaegerInterface, name string, fn func(context.Context) (T, error), opts ...RunOption) (result T, err error) {
+	cfg := runConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	ctx, span := tracer.Trace(ctx, name)
+	if len(cfg.attrs) > 0 {
+		span.SetAttributes(cfg.attrs...)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			recordPanic(span, r)
+			span.End()
+			panic(r)
+		}
+
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	return fn(ctx)
+}
+
+// recordPanic adds an exception event the same way RecordError does, using
+// the stack of the panicking goroutine
+func recordPanic(span trace.Span, r any) {
+	message := fmt.Sprint(r)
+
+	span.AddEvent(semconv.ExceptionEventName, trace.WithAttributes(
+		semconv.ExceptionType(fmt.Sprintf("%T", r)),
+		semconv.ExceptionMessage(message),
+		semconv.ExceptionStacktrace(string(debug.Stack())),
+		attribute.Bool("exception.escaped", true),
+	))
+	span.SetStatus(codes.Error, "panic: "+message)
+}
@@ -0,0 +1,79 @@
+package jaeger
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestRunRecordsError(t *testing.T) {
+	obs, exporter := newTestObs(t)
+	errNotFound := errors.New("not found")
+
+	err := Run(context.Background(), obs, "UserService.Delete", func(ctx context.Context) error {
+		require.True(t, trace.SpanContextFromContext(ctx).IsValid())
+		return errNotFound
+	}, WithAttributes(attribute.Int("user.id", 42)))
+	require.ErrorIs(t, err, errNotFound)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	require.Equal(t, "UserService.Delete", spans[0].Name)
+	require.Equal(t, codes.Error, spans[0].Status.Code)
+	require.Equal(t, "exception", spans[0].Events[0].Name)
+
+	id, ok := spanAttr(spans[0], "user.id")
+	require.True(t, ok)
+	require.Equal(t, int64(42), id.AsInt64())
+}
+
+func TestRunValueReturnsResult(t *testing.T) {
+	obs, exporter := newTestObs(t)
+
+	name, err := RunValue(context.Background(), obs, "UserService.Get", func(ctx context.Context) (string, error) {
+		return "john", nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "john", name)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	require.Equal(t, codes.Unset, spans[0].Status.Code)
+	require.Empty(t, spans[0].Events)
+}
+
+func TestRunRecordsPanic(t *testing.T) {
+	obs, exporter := newTestObs(t)
+
+	require.PanicsWithValue(t, "boom", func() {
+		_ = Run(context.Background(), obs, "Worker.Process", func(ctx context.Context) error {
+			panic("boom")
+		})
+	})
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	require.Equal(t, codes.Error, spans[0].Status.Code)
+
+	event := spans[0].Events[0]
+	require.Equal(t, "exception", event.Name)
+
+	attrs := attribute.NewSet(event.Attributes...)
+	message, _ := attrs.Value("exception.message")
+	stack, _ := attrs.Value("exception.stacktrace")
+	require.Equal(t, "boom", message.AsString())
+	require.True(t, strings.Contains(stack.AsString(), "TestRunRecordsPanic"))
+}
+
+func TestRunWithMockTracer(t *testing.T) {
+	err := Run(context.Background(), NewMockTracer(), "noop", func(ctx context.Context) error {
+		return nil
+	})
+	require.NoError(t, err)
+}
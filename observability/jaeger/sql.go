@@ -0,0 +1,496 @@
+package jaeger
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	dbRowsAffectedKey = attribute.Key("db.rows_affected")
+	dbRowsReturnedKey = attribute.Key("db.rows_returned")
+)
+
+// WrapDriver returns a database/sql driver that traces every query, exec,
+// prepare and transaction made through d. system is reported as db.system,
+// e.g. "postgresql" or "mysql".
+//
+//	sql.Register("postgres-traced", tracer.WrapDriver(&pq.Driver{}, "postgresql"))
+//	db, err := sql.Open("postgres-traced", dsn)
+func (t JaegerObs) WrapDriver(d driver.Driver, system string) driver.Driver {
+	return &tracedDriver{Driver: d, db: sqlTracer{obs: t, system: system}}
+}
+
+// WrapConnector is the sql.OpenDB counterpart of WrapDriver
+//
+//	db := sql.OpenDB(tracer.WrapConnector(connector, "postgresql"))
+func (t JaegerObs) WrapConnector(c driver.Connector, system string) driver.Connector {
+	db := sqlTracer{obs: t, system: system}
+	return &tracedConnector{
+		Connector: c,
+		driver:    &tracedDriver{Driver: c.Driver(), db: db},
+		db:        db,
+	}
+}
+
+// sqlTracer starts the spans for every wrapped driver type
+type sqlTracer struct {
+	obs    JaegerObs
+	system string
+}
+
+func (s sqlTracer) start(ctx context.Context, operation, query string, args []driver.NamedValue) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{
+		semconv.DBSystemKey.String(s.system),
+		semconv.DBOperationName(operation),
+	}
+	if query != "" {
+		attrs = append(attrs, attribute.String("db.statement", query))
+	}
+	if len(args) > 0 {
+		attrs = append(attrs, attribute.String("db.args", s.obs.maskArgs(namedValueArgs(args))))
+	}
+
+	return s.obs.tp.Tracer(s.obs.cfg.Name).Start(ctx, operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+// end records err on span unless it only asks database/sql to fall back
+func (s sqlTracer) end(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, driver.ErrSkip) && !errors.Is(err, io.EOF) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// sqlOperation returns the leading keyword of query, e.g. SELECT or INSERT
+func sqlOperation(query string) string {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return "QUERY"
+	}
+	return strings.ToUpper(strings.TrimLeft(fields[0], "("))
+}
+
+// namedValueArgs keeps ordinal args as a slice and named args as a map so the
+// masking of keyword named parameters still applies
+func namedValueArgs(args []driver.NamedValue) any {
+	named := make(map[string]any)
+	values := make([]any, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+		if arg.Name != "" {
+			named[arg.Name] = arg.Value
+		}
+	}
+
+	if len(named) == len(args) {
+		return named
+	}
+	return values
+}
+
+func namedToValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, errors.New("jaeger: driver does not support named parameters")
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
+
+type tracedDriver struct {
+	driver.Driver
+	db sqlTracer
+}
+
+func (d *tracedDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedConn{Conn: conn, db: d.db}, nil
+}
+
+func (d *tracedDriver) OpenConnector(name string) (driver.Connector, error) {
+	if dc, ok := d.Driver.(driver.DriverContext); ok {
+		connector, err := dc.OpenConnector(name)
+		if err != nil {
+			return nil, err
+		}
+		return &tracedConnector{Connector: connector, driver: d, db: d.db}, nil
+	}
+
+	return &dsnConnector{name: name, driver: d}, nil
+}
+
+type tracedConnector struct {
+	driver.Connector
+	driver driver.Driver
+	db     sqlTracer
+}
+
+func (c *tracedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedConn{Conn: conn, db: c.db}, nil
+}
+
+func (c *tracedConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// dsnConnector mirrors the connector database/sql builds for drivers without
+// driver.DriverContext
+type dsnConnector struct {
+	name   string
+	driver *tracedDriver
+}
+
+func (c *dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.name)
+}
+
+func (c *dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+type tracedConn struct {
+	driver.Conn
+	db sqlTracer
+}
+
+func (c *tracedConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *tracedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	_, span := c.db.start(ctx, "PREPARE", query, nil)
+
+	var stmt driver.Stmt
+	var err error
+	if cp, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = cp.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+
+	c.db.end(span, err)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedStmt{Stmt: stmt, query: query, db: c.db}, nil
+}
+
+func (c *tracedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, isCtx := c.Conn.(driver.ExecerContext)
+	legacy, isLegacy := c.Conn.(driver.Execer)
+	if !isCtx && !isLegacy {
+		return nil, driver.ErrSkip
+	}
+
+	_, span := c.db.start(ctx, sqlOperation(query), query, args)
+
+	var res driver.Result
+	var err error
+	if isCtx {
+		res, err = execer.ExecContext(ctx, query, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedToValues(args); err == nil {
+			res, err = legacy.Exec(query, values)
+		}
+	}
+
+	if err == nil {
+		if affected, rerr := res.RowsAffected(); rerr == nil {
+			span.SetAttributes(dbRowsAffectedKey.Int64(affected))
+		}
+	}
+
+	c.db.end(span, err)
+	return res, err
+}
+
+func (c *tracedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, isCtx := c.Conn.(driver.QueryerContext)
+	legacy, isLegacy := c.Conn.(driver.Queryer)
+	if !isCtx && !isLegacy {
+		return nil, driver.ErrSkip
+	}
+
+	_, span := c.db.start(ctx, sqlOperation(query), query, args)
+
+	var rows driver.Rows
+	var err error
+	if isCtx {
+		rows, err = queryer.QueryContext(ctx, query, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedToValues(args); err == nil {
+			rows, err = legacy.Query(query, values)
+		}
+	}
+
+	if err != nil {
+		c.db.end(span, err)
+		return nil, err
+	}
+	return &tracedRows{Rows: rows, span: span, db: c.db}, nil
+}
+
+func (c *tracedConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *tracedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	ctx, span := c.db.obs.tp.Tracer(c.db.obs.cfg.Name).Start(ctx, "TRANSACTION",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemKey.String(c.db.system),
+			semconv.DBOperationName("BEGIN"),
+		),
+	)
+
+	var tx driver.Tx
+	var err error
+	if cb, ok := c.Conn.(driver.ConnBeginTx); ok {
+		tx, err = cb.BeginTx(ctx, opts)
+	} else if opts.Isolation != driver.IsolationLevel(0) || opts.ReadOnly {
+		err = errors.New("jaeger: driver does not support non-default transaction options")
+	} else {
+		tx, err = c.Conn.Begin()
+	}
+
+	if err != nil {
+		c.db.end(span, err)
+		return nil, err
+	}
+
+	return &tracedTx{Tx: tx, ctx: ctx, span: span, db: c.db}, nil
+}
+
+func (c *tracedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *tracedConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *tracedConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+func (c *tracedConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// tracedTx keeps the transaction span opened by BeginTx alive until the
+// transaction is committed or rolled back
+type tracedTx struct {
+	driver.Tx
+	ctx  context.Context
+	span trace.Span
+	db   sqlTracer
+}
+
+func (tx *tracedTx) Commit() error {
+	return tx.finish("COMMIT", tx.Tx.Commit)
+}
+
+func (tx *tracedTx) Rollback() error {
+	return tx.finish("ROLLBACK", tx.Tx.Rollback)
+}
+
+func (tx *tracedTx) finish(operation string, fn func() error) error {
+	_, span := tx.db.start(tx.ctx, operation, "", nil)
+	err := fn()
+	tx.db.end(span, err)
+
+	tx.span.SetAttributes(attribute.String("db.transaction.outcome", strings.ToLower(operation)))
+	tx.db.end(tx.span, err)
+	return err
+}
+
+type tracedStmt struct {
+	driver.Stmt
+	query string
+	db    sqlTracer
+}
+
+func (s *tracedStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), valuesToNamed(args))
+}
+
+func (s *tracedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	_, span := s.db.start(ctx, sqlOperation(s.query), s.query, args)
+
+	var res driver.Result
+	var err error
+	if se, ok := s.Stmt.(driver.StmtExecContext); ok {
+		res, err = se.ExecContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedToValues(args); err == nil {
+			res, err = s.Stmt.Exec(values)
+		}
+	}
+
+	if err == nil {
+		if affected, rerr := res.RowsAffected(); rerr == nil {
+			span.SetAttributes(dbRowsAffectedKey.Int64(affected))
+		}
+	}
+
+	s.db.end(span, err)
+	return res, err
+}
+
+func (s *tracedStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), valuesToNamed(args))
+}
+
+func (s *tracedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	_, span := s.db.start(ctx, sqlOperation(s.query), s.query, args)
+
+	var rows driver.Rows
+	var err error
+	if sq, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = sq.QueryContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedToValues(args); err == nil {
+			rows, err = s.Stmt.Query(values)
+		}
+	}
+
+	if err != nil {
+		s.db.end(span, err)
+		return nil, err
+	}
+	return &tracedRows{Rows: rows, span: span, db: s.db}, nil
+}
+
+func (s *tracedStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+func valuesToNamed(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return named
+}
+
+// tracedRows keeps the query span open until the rows are closed so it covers
+// the whole read and knows how many rows were returned
+type tracedRows struct {
+	driver.Rows
+	span  trace.Span
+	db    sqlTracer
+	count int64
+	err   error
+}
+
+func (r *tracedRows) Next(dest []driver.Value) error {
+	err := r.Rows.Next(dest)
+	if err == nil {
+		r.count++
+	} else if err != io.EOF {
+		r.err = err
+	}
+	return err
+}
+
+func (r *tracedRows) Close() error {
+	err := r.Rows.Close()
+
+	r.span.SetAttributes(dbRowsReturnedKey.Int64(r.count))
+	if r.err == nil {
+		r.err = err
+	}
+	r.db.end(r.span, r.err)
+
+	return err
+}
+
+func (r *tracedRows) HasNextResultSet() bool {
+	if rs, ok := r.Rows.(driver.RowsNextResultSet); ok {
+		return rs.HasNextResultSet()
+	}
+	return false
+}
+
+func (r *tracedRows) NextResultSet() error {
+	if rs, ok := r.Rows.(driver.RowsNextResultSet); ok {
+		return rs.NextResultSet()
+	}
+	return io.EOF
+}
+
+func (r *tracedRows) ColumnTypeScanType(index int) reflect.Type {
+	if ct, ok := r.Rows.(driver.RowsColumnTypeScanType); ok {
+		return ct.ColumnTypeScanType(index)
+	}
+	return reflect.TypeFor[any]()
+}
+
+func (r *tracedRows) ColumnTypeDatabaseTypeName(index int) string {
+	if ct, ok := r.Rows.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		return ct.ColumnTypeDatabaseTypeName(index)
+	}
+	return ""
+}
+
+func (r *tracedRows) ColumnTypeLength(index int) (int64, bool) {
+	if ct, ok := r.Rows.(driver.RowsColumnTypeLength); ok {
+		return ct.ColumnTypeLength(index)
+	}
+	return 0, false
+}
+
+func (r *tracedRows) ColumnTypeNullable(index int) (bool, bool) {
+	if ct, ok := r.Rows.(driver.RowsColumnTypeNullable); ok {
+		return ct.ColumnTypeNullable(index)
+	}
+	return false, false
+}
+
+func (r *tracedRows) ColumnTypePrecisionScale(index int) (int64, int64, bool) {
+	if ct, ok := r.Rows.(driver.RowsColumnTypePrecisionScale); ok {
+		return ct.ColumnTypePrecisionScale(index)
+	}
+	return 0, 0, false
+}
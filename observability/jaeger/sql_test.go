@@ -0,0 +1,135 @@
+package jaeger
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// fakeDriver answers every query with two rows and fails on "BROKEN" statements
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{}, nil }
+
+type fakeConnector struct{}
+
+func (fakeConnector) Connect(context.Context) (driver.Conn, error) { return fakeConn{}, nil }
+func (fakeConnector) Driver() driver.Driver                        { return fakeDriver{} }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{}, nil }
+func (fakeConn) Close() error                              { return nil }
+func (fakeConn) Begin() (driver.Tx, error)                 { return fakeTx{}, nil }
+
+func (fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if query == "BROKEN" {
+		return nil, errors.New("syntax error")
+	}
+	return driver.RowsAffected(3), nil
+}
+
+func (fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return &fakeRows{}, nil
+}
+
+type fakeStmt struct{}
+
+func (fakeStmt) Close() error                                    { return nil }
+func (fakeStmt) NumInput() int                                   { return -1 }
+func (fakeStmt) Exec(args []driver.Value) (driver.Result, error) { return driver.RowsAffected(1), nil }
+func (fakeStmt) Query(args []driver.Value) (driver.Rows, error)  { return &fakeRows{}, nil }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeRows struct{ n int }
+
+func (r *fakeRows) Columns() []string { return []string{"id"} }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.n == 2 {
+		return io.EOF
+	}
+	r.n++
+	dest[0] = int64(r.n)
+	return nil
+}
+
+func TestWrapDriverTracesQueries(t *testing.T) {
+	obs, exporter := newTestObs(t)
+	obs = obs.WithConfig(JaegerConfig{Name: "test", SensitiveKeywords: []string{"password"}}).(JaegerObs)
+
+	db := sql.OpenDB(obs.WrapConnector(fakeConnector{}, "postgresql"))
+	defer db.Close()
+	ctx := context.Background()
+
+	rows, err := db.QueryContext(ctx, "SELECT id FROM users WHERE password = $1", sql.Named("password", "secret"))
+	require.NoError(t, err)
+	for rows.Next() {
+	}
+	require.NoError(t, rows.Close())
+
+	_, err = db.ExecContext(ctx, "UPDATE users SET active = true")
+	require.NoError(t, err)
+
+	_, err = db.ExecContext(ctx, "BROKEN")
+	require.Error(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 3)
+
+	require.Equal(t, "SELECT", spans[0].Name)
+	system, _ := spanAttr(spans[0], "db.system")
+	returned, _ := spanAttr(spans[0], "db.rows_returned")
+	args, _ := spanAttr(spans[0], "db.args")
+	require.Equal(t, "postgresql", system.AsString())
+	require.Equal(t, int64(2), returned.AsInt64())
+	require.Equal(t, `{"password":"***"}`, args.AsString())
+
+	require.Equal(t, "UPDATE", spans[1].Name)
+	affected, _ := spanAttr(spans[1], "db.rows_affected")
+	require.Equal(t, int64(3), affected.AsInt64())
+
+	require.Equal(t, codes.Error, spans[2].Status.Code)
+}
+
+func TestWrapDriverTracesTransactions(t *testing.T) {
+	obs, exporter := newTestObs(t)
+
+	sql.Register("jaeger-fake", obs.WrapDriver(fakeDriver{}, "sqlite"))
+	db, err := sql.Open("jaeger-fake", "")
+	require.NoError(t, err)
+	defer db.Close()
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+
+	stmt, err := tx.Prepare("INSERT INTO users VALUES (?)")
+	require.NoError(t, err)
+	_, err = stmt.Exec(1)
+	require.NoError(t, err)
+	require.NoError(t, stmt.Close())
+	require.NoError(t, tx.Commit())
+
+	names := []string{}
+	for _, span := range exporter.GetSpans() {
+		names = append(names, span.Name)
+	}
+	require.Equal(t, []string{"PREPARE", "INSERT", "COMMIT", "TRANSACTION"}, names)
+
+	spans := exporter.GetSpans()
+	commit, transaction := spans[2], spans[3]
+	require.Equal(t, transaction.SpanContext.SpanID(), commit.Parent.SpanID())
+
+	outcome, _ := spanAttr(transaction, "db.transaction.outcome")
+	require.Equal(t, "commit", outcome.AsString())
+}
@@ -0,0 +1,280 @@
+package jaeger
+
+import (
+	"container/list"
+	"context"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultTailWindow           = 5 * time.Second
+	defaultTailMaxTraces        = 10000
+	defaultTailMaxSpansPerTrace = 1000
+	// minTailSweepInterval keeps tiny windows from spinning the sweeper,
+	// time.NewTicker also panics on a zero interval
+	minTailSweepInterval = 10 * time.Millisecond
+)
+
+// TailSamplingConfig configures the error biased tail sampling processor.
+// A trace is kept when any of its spans errored or lasted at least
+// LatencyThreshold, the remaining traces are kept at SampleRatio.
+type TailSamplingConfig struct {
+	// Window is how long spans of a trace are buffered waiting for its local
+	// root span, defaults to 5 seconds
+	Window time.Duration
+	// LatencyThreshold keeps traces with a span at least this slow, 0 disables it
+	LatencyThreshold time.Duration
+	// SampleRatio of healthy traces kept, between 0 and 1
+	SampleRatio float64
+	// MaxTraces bounds the traces buffered at once, the oldest one is decided
+	// early when the limit is hit, defaults to 10000
+	MaxTraces int
+	// MaxSpansPerTrace bounds the spans buffered for a single trace, the trace
+	// is decided early when the limit is hit, defaults to 1000
+	MaxSpansPerTrace int
+}
+
+// TailSamplingProcessor buffers ended spans per trace and forwards whole
+// traces to the next processor once it decided to keep them
+type TailSamplingProcessor struct {
+	next sdktrace.SpanProcessor
+	cfg  TailSamplingConfig
+	now  func() time.Time
+	// sampledOut is told how many spans were not kept, may be nil
+	sampledOut func(spans int)
+
+	mu      sync.Mutex
+	pending map[trace.TraceID]*list.Element
+	order   *list.List
+	decided map[trace.TraceID]tailDecision
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+type pendingTrace struct {
+	id    trace.TraceID
+	first time.Time
+	spans []sdktrace.ReadOnlySpan
+}
+
+type tailDecision struct {
+	keep bool
+	at   time.Time
+}
+
+// NewTailSamplingProcessor wraps next, usually a batch span processor, with
+// tail sampling. The tracer provider must sample every span for the decision
+// to see whole traces.
+func NewTailSamplingProcessor(next sdktrace.SpanProcessor, cfg TailSamplingConfig) *TailSamplingProcessor {
+	if cfg.Window <= 0 {
+		cfg.Window = defaultTailWindow
+	}
+	if cfg.MaxTraces <= 0 {
+		cfg.MaxTraces = defaultTailMaxTraces
+	}
+	if cfg.MaxSpansPerTrace <= 0 {
+		cfg.MaxSpansPerTrace = defaultTailMaxSpansPerTrace
+	}
+
+	p := &TailSamplingProcessor{
+		next:    next,
+		cfg:     cfg,
+		now:     time.Now,
+		pending: make(map[trace.TraceID]*list.Element),
+		order:   list.New(),
+		decided: make(map[trace.TraceID]tailDecision),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go p.run()
+
+	return p
+}
+
+func (p *TailSamplingProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	p.next.OnStart(parent, s)
+}
+
+func (p *TailSamplingProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	id := s.SpanContext().TraceID()
+
+	p.mu.Lock()
+
+	// late spans of an already decided trace follow that decision
+	if decision, ok := p.decided[id]; ok {
+		p.mu.Unlock()
+		if decision.keep {
+			p.next.OnEnd(s)
+		} else {
+			p.sampleOut(1)
+		}
+		return
+	}
+
+	var flush [][]sdktrace.ReadOnlySpan
+
+	elem, ok := p.pending[id]
+	if !ok {
+		if p.order.Len() >= p.cfg.MaxTraces {
+			flush = append(flush, p.decideLocked(p.order.Front()))
+		}
+		elem = p.order.PushBack(&pendingTrace{id: id, first: p.now()})
+		p.pending[id] = elem
+	}
+
+	pt := elem.Value.(*pendingTrace)
+	pt.spans = append(pt.spans, s)
+
+	parent := s.Parent()
+	if !parent.IsValid() || parent.IsRemote() || len(pt.spans) >= p.cfg.MaxSpansPerTrace {
+		flush = append(flush, p.decideLocked(elem))
+	}
+
+	p.mu.Unlock()
+
+	p.forward(flush)
+}
+
+// Shutdown decides every buffered trace and shuts the next processor down
+func (p *TailSamplingProcessor) Shutdown(ctx context.Context) error {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+		<-p.done
+	})
+
+	p.forward(p.decideAll())
+	return p.next.Shutdown(ctx)
+}
+
+// ForceFlush decides every buffered trace and flushes the next processor
+func (p *TailSamplingProcessor) ForceFlush(ctx context.Context) error {
+	p.forward(p.decideAll())
+	return p.next.ForceFlush(ctx)
+}
+
+// sweepInterval checks buffered traces twice per window
+func sweepInterval(window time.Duration) time.Duration {
+	return max(window/2, minTailSweepInterval)
+}
+
+func (p *TailSamplingProcessor) run() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(sweepInterval(p.cfg.Window))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.forward(p.sweep())
+		}
+	}
+}
+
+// sweep decides the traces whose window elapsed and forgets old decisions
+func (p *TailSamplingProcessor) sweep() [][]sdktrace.ReadOnlySpan {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+
+	var flush [][]sdktrace.ReadOnlySpan
+	for elem := p.order.Front(); elem != nil; {
+		next := elem.Next()
+		if now.Sub(elem.Value.(*pendingTrace).first) < p.cfg.Window {
+			// traces are ordered by arrival, the rest is younger
+			break
+		}
+		flush = append(flush, p.decideLocked(elem))
+		elem = next
+	}
+
+	for id, decision := range p.decided {
+		if now.Sub(decision.at) >= p.cfg.Window {
+			delete(p.decided, id)
+		}
+	}
+
+	return flush
+}
+
+func (p *TailSamplingProcessor) decideAll() [][]sdktrace.ReadOnlySpan {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var flush [][]sdktrace.ReadOnlySpan
+	for p.order.Len() > 0 {
+		flush = append(flush, p.decideLocked(p.order.Front()))
+	}
+	return flush
+}
+
+// decideLocked removes the trace from the buffer and returns its spans when
+// it is kept. p.mu must be held.
+func (p *TailSamplingProcessor) decideLocked(elem *list.Element) []sdktrace.ReadOnlySpan {
+	pt := p.order.Remove(elem).(*pendingTrace)
+	delete(p.pending, pt.id)
+
+	keep := p.keep(pt)
+	if len(p.decided) < p.cfg.MaxTraces {
+		p.decided[pt.id] = tailDecision{keep: keep, at: p.now()}
+	}
+
+	if !keep {
+		p.sampleOut(len(pt.spans))
+		return nil
+	}
+	return pt.spans
+}
+
+func (p *TailSamplingProcessor) sampleOut(spans int) {
+	if p.sampledOut != nil {
+		p.sampledOut(spans)
+	}
+}
+
+func (p *TailSamplingProcessor) keep(pt *pendingTrace) bool {
+	for _, s := range pt.spans {
+		if s.Status().Code == codes.Error {
+			return true
+		}
+		if p.cfg.LatencyThreshold > 0 && s.EndTime().Sub(s.StartTime()) >= p.cfg.LatencyThreshold {
+			return true
+		}
+	}
+
+	return sampleTraceID(pt.id, p.cfg.SampleRatio)
+}
+
+func (p *TailSamplingProcessor) forward(traces [][]sdktrace.ReadOnlySpan) {
+	for _, spans := range traces {
+		for _, s := range spans {
+			p.next.OnEnd(s)
+		}
+	}
+}
+
+// sampleTraceID makes the same call as sdktrace.TraceIDRatioBased so every
+// service keeps the same healthy traces
+func sampleTraceID(id trace.TraceID, ratio float64) bool {
+	if ratio >= 1 {
+		return true
+	}
+	if ratio <= 0 {
+		return false
+	}
+
+	bound := uint64(ratio * (1 << 63))
+	x := binary.BigEndian.Uint64(id[8:16]) >> 1
+	return x < bound
+}
@@ -0,0 +1,165 @@
+package jaeger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newTailSampling(t *testing.T, cfg TailSamplingConfig) (*TailSamplingProcessor, trace.Tracer, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	processor := NewTailSamplingProcessor(sdktrace.NewSimpleSpanProcessor(exporter), cfg)
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(processor))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	return processor, tp.Tracer("test"), exporter
+}
+
+func TestTailSamplingKeepsFailedTraces(t *testing.T) {
+	_, tracer, exporter := newTailSampling(t, TailSamplingConfig{SampleRatio: 0})
+
+	// healthy trace is dropped
+	ctx, root := tracer.Start(context.Background(), "healthy")
+	_, child := tracer.Start(ctx, "healthy.child")
+	child.End()
+	root.End()
+	require.Empty(t, exporter.GetSpans())
+
+	// one failed child keeps the whole trace
+	ctx, root = tracer.Start(context.Background(), "failed")
+	_, child = tracer.Start(ctx, "failed.child")
+	child.SetStatus(codes.Error, "boom")
+	child.End()
+	require.Empty(t, exporter.GetSpans())
+	root.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	require.Equal(t, "failed.child", spans[0].Name)
+	require.Equal(t, "failed", spans[1].Name)
+}
+
+func TestTailSamplingKeepsSlowTraces(t *testing.T) {
+	_, tracer, exporter := newTailSampling(t, TailSamplingConfig{LatencyThreshold: time.Second})
+
+	start := time.Now()
+	_, fast := tracer.Start(context.Background(), "fast", trace.WithTimestamp(start))
+	fast.End(trace.WithTimestamp(start.Add(10 * time.Millisecond)))
+
+	_, slow := tracer.Start(context.Background(), "slow", trace.WithTimestamp(start))
+	slow.End(trace.WithTimestamp(start.Add(2 * time.Second)))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	require.Equal(t, "slow", spans[0].Name)
+}
+
+func TestTailSamplingRatio(t *testing.T) {
+	_, tracer, exporter := newTailSampling(t, TailSamplingConfig{SampleRatio: 1})
+
+	_, span := tracer.Start(context.Background(), "healthy")
+	span.End()
+
+	require.Len(t, exporter.GetSpans(), 1)
+}
+
+func TestTailSamplingDecidesAfterWindow(t *testing.T) {
+	processor, tracer, exporter := newTailSampling(t, TailSamplingConfig{Window: time.Hour})
+
+	now := time.Now()
+	processor.now = func() time.Time { return now }
+
+	// the root never ends, only the window can decide this trace
+	ctx, root := tracer.Start(context.Background(), "stuck")
+	defer root.End()
+	_, child := tracer.Start(ctx, "stuck.child")
+	child.SetStatus(codes.Error, "boom")
+	child.End()
+
+	processor.forward(processor.sweep())
+	require.Empty(t, exporter.GetSpans())
+
+	now = now.Add(time.Hour)
+	processor.forward(processor.sweep())
+	require.Len(t, exporter.GetSpans(), 1)
+
+	// late spans follow the decision made for their trace
+	_, late := tracer.Start(ctx, "stuck.late")
+	late.End()
+	require.Len(t, exporter.GetSpans(), 2)
+}
+
+func TestTailSamplingTinyWindow(t *testing.T) {
+	require.Equal(t, minTailSweepInterval, sweepInterval(time.Nanosecond))
+	require.Equal(t, time.Second, sweepInterval(2*time.Second))
+
+	// a 1ns window used to panic in time.NewTicker
+	_, tracer, exporter := newTailSampling(t, TailSamplingConfig{Window: time.Nanosecond})
+
+	_, span := tracer.Start(context.Background(), "fast")
+	span.SetStatus(codes.Error, "boom")
+	span.End()
+	require.Len(t, exporter.GetSpans(), 1)
+}
+
+func TestTailSamplingIsBounded(t *testing.T) {
+	processor, tracer, exporter := newTailSampling(t, TailSamplingConfig{
+		Window:           time.Hour,
+		MaxTraces:        2,
+		MaxSpansPerTrace: 3,
+	})
+
+	roots := []trace.Span{}
+	for range 3 {
+		ctx, root := tracer.Start(context.Background(), "root")
+		roots = append(roots, root)
+
+		_, child := tracer.Start(ctx, "child")
+		child.SetStatus(codes.Error, "boom")
+		child.End()
+	}
+	defer func() {
+		for _, root := range roots {
+			root.End()
+		}
+	}()
+
+	// the third trace pushed the oldest one out of the buffer
+	require.Len(t, exporter.GetSpans(), 1)
+	require.Equal(t, 2, processor.order.Len())
+
+	ctx, root := tracer.Start(context.Background(), "wide")
+	defer root.End()
+	for range 3 {
+		_, child := tracer.Start(ctx, "wide.child")
+		child.SetStatus(codes.Error, "boom")
+		child.End()
+	}
+
+	// the wide trace evicted the next oldest one, then hitting
+	// MaxSpansPerTrace decided it without waiting for its root
+	require.Len(t, exporter.GetSpans(), 5)
+	require.LessOrEqual(t, processor.order.Len(), 2)
+}
+
+func TestTailSamplingShutdownFlushesPending(t *testing.T) {
+	processor, tracer, exporter := newTailSampling(t, TailSamplingConfig{Window: time.Hour, SampleRatio: 1})
+
+	ctx, root := tracer.Start(context.Background(), "root")
+	_, child := tracer.Start(ctx, "child")
+	child.End()
+	require.Empty(t, exporter.GetSpans())
+
+	require.NoError(t, processor.ForceFlush(context.Background()))
+	require.Len(t, exporter.GetSpans(), 1)
+
+	root.End()
+	require.NoError(t, processor.Shutdown(context.Background()))
+}
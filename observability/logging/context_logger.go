@@ -1,16 +1,24 @@
 package logging
 
 import (
+	"context"
+	"fmt"
+	"maps"
 	"os"
 	"time"
 
+	"github.com/bolanosdev/go-snacks/errors"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/baggage"
 )
 
 // ContextLogger wraps zerolog.Logger with trace ID context
 type ContextLogger struct {
 	logger  zerolog.Logger
 	traceID string
+	// fields added to the logger context, forwarded to sentry as extras
+	fields map[string]interface{}
+	sentry *sentryForwarder
 }
 
 // NewContextLogger creates a new ContextLogger with the given trace ID
@@ -51,96 +59,175 @@ func NewContextLogger(traceID string, mode string) *ContextLogger {
 	}
 }
 
+// WithBaggage returns a ContextLogger that adds the given baggage keys of ctx
+// to every log entry, so logs can be filtered by the same tenant or user
+// attributes as spans. Keys missing from the baggage are skipped.
+func (cl *ContextLogger) WithBaggage(ctx context.Context, keys ...string) *ContextLogger {
+	bag := baggage.FromContext(ctx)
+
+	logCtx := cl.logger.With()
+	fields := maps.Clone(cl.fields)
+	for _, key := range keys {
+		member := bag.Member(key)
+		if member.Key() == "" {
+			continue
+		}
+		logCtx = logCtx.Str(key, member.Value())
+
+		if fields == nil {
+			fields = map[string]interface{}{}
+		}
+		fields[key] = member.Value()
+	}
+
+	return &ContextLogger{
+		logger:  logCtx.Logger(),
+		traceID: cl.traceID,
+		fields:  fields,
+		sentry:  cl.sentry,
+	}
+}
+
 // Event wraps zerolog.Event to automatically add trace_id
 type Event struct {
 	event   *zerolog.Event
 	traceID string
+	level   zerolog.Level
+	// fields and err are only kept when the entry is forwarded to sentry
+	fields map[string]interface{}
+	err    error
+	sentry *sentryForwarder
 }
 
 // Error starts a new error-level log entry
 func (cl *ContextLogger) Error() *Event {
-	return &Event{
-		event:   cl.logger.Error().CallerSkipFrame(1).Str("trace_id", cl.traceID),
-		traceID: cl.traceID,
-	}
+	return cl.newEvent(zerolog.ErrorLevel, cl.logger.Error())
 }
 
 // Info starts a new info-level log entry
 func (cl *ContextLogger) Info() *Event {
-	return &Event{
-		event:   cl.logger.Info().CallerSkipFrame(1).Str("trace_id", cl.traceID),
-		traceID: cl.traceID,
-	}
+	return cl.newEvent(zerolog.InfoLevel, cl.logger.Info())
 }
 
 // Debug starts a new debug-level log entry
 func (cl *ContextLogger) Debug() *Event {
-	return &Event{
-		event:   cl.logger.Debug().CallerSkipFrame(1).Str("trace_id", cl.traceID),
-		traceID: cl.traceID,
-	}
+	return cl.newEvent(zerolog.DebugLevel, cl.logger.Debug())
 }
 
 // Warn starts a new warn-level log entry
 func (cl *ContextLogger) Warn() *Event {
-	return &Event{
-		event:   cl.logger.Warn().CallerSkipFrame(1).Str("trace_id", cl.traceID),
+	return cl.newEvent(zerolog.WarnLevel, cl.logger.Warn())
+}
+
+func (cl *ContextLogger) newEvent(level zerolog.Level, event *zerolog.Event) *Event {
+	e := &Event{
+		event:   event.CallerSkipFrame(1).Str("trace_id", cl.traceID),
 		traceID: cl.traceID,
+		level:   level,
+	}
+
+	if cl.sentry != nil && event.Enabled() {
+		e.sentry = cl.sentry
+		e.fields = maps.Clone(cl.fields)
+		e.record("trace_id", cl.traceID)
+	}
+
+	return e
+}
+
+// record keeps a field for the sentry event or breadcrumb of the entry
+func (e *Event) record(key string, val interface{}) {
+	if e.sentry == nil {
+		return
+	}
+	if e.fields == nil {
+		e.fields = map[string]interface{}{}
 	}
+	e.fields[key] = val
 }
 
-// Err adds an error to the log entry
+// Err adds an error to the log entry, a structured error from the errors
+// package also adds its code, statuses, metadata and error_stack
 func (e *Event) Err(err error) *Event {
 	e.event = e.event.Err(err)
+	if e.sentry != nil {
+		e.err = err
+	}
+
+	var structured *errors.Error
+	if !errors.As(err, &structured) {
+		return e
+	}
+
+	e.event = e.event.Fields(errors.Fields(structured))
+
+	frames := structured.StackTrace()
+	stack := make([]string, len(frames))
+	for i, frame := range frames {
+		stack[i] = fmt.Sprintf("%n %s:%d", frame, frame, frame)
+	}
+	e.event = e.event.Strs("error_stack", stack)
+
 	return e
 }
 
 // Str adds a string field to the log entry
 func (e *Event) Str(key, val string) *Event {
 	e.event = e.event.Str(key, val)
+	e.record(key, val)
 	return e
 }
 
 // Int adds an int field to the log entry
 func (e *Event) Int(key string, val int) *Event {
 	e.event = e.event.Int(key, val)
+	e.record(key, val)
 	return e
 }
 
 func (e *Event) Dur(key string, val time.Duration) *Event {
 	e.event = e.event.Dur(key, val)
+	e.record(key, val)
 	return e
 }
 
 // Bool adds a bool field to the log entry
 func (e *Event) Bool(key string, val bool) *Event {
 	e.event = e.event.Bool(key, val)
+	e.record(key, val)
 	return e
 }
 
 // Interface adds an interface{} field to the log entry
 func (e *Event) Interface(key string, val interface{}) *Event {
 	e.event = e.event.Interface(key, val)
+	e.record(key, val)
 	return e
 }
 
 // WithData adds arbitrary data to the log entry
 func (e *Event) WithData(key string, val interface{}) *Event {
 	e.event = e.event.Interface(key, val)
+	e.record(key, val)
 	return e
 }
 
 // Msg sends the log entry with the given message
 func (e *Event) Msg(msg string) {
 	e.event.Msg(msg)
+	e.forward(msg)
 }
 
 // Msgf sends the log entry with a formatted message
 func (e *Event) Msgf(format string, v ...interface{}) {
 	e.event.Msgf(format, v...)
+	if e.sentry != nil {
+		e.forward(fmt.Sprintf(format, v...))
+	}
 }
 
 // Send sends the log entry without a message
 func (e *Event) Send() {
 	e.event.Send()
+	e.forward("")
 }
@@ -2,13 +2,18 @@ package logging
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
+	snackerrors "github.com/bolanosdev/go-snacks/errors"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/baggage"
 )
 
 func TestNewContextLogger(t *testing.T) {
@@ -144,6 +149,46 @@ func TestContextLoggerWithBuffer(t *testing.T) {
 	})
 }
 
+func TestContextLoggerWithBaggage(t *testing.T) {
+	var buf bytes.Buffer
+	logger := &ContextLogger{
+		logger:  zerolog.New(&buf),
+		traceID: "trace-789",
+	}
+
+	tenant, _ := baggage.NewMemberRaw("tenant.id", "acme")
+	session, _ := baggage.NewMemberRaw("session", "secret")
+	bag, _ := baggage.New(tenant, session)
+	ctx := baggage.ContextWithBaggage(context.Background(), bag)
+
+	logger.WithBaggage(ctx, "tenant.id", "user.id").Info().Msg("with baggage")
+
+	var logEntry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &logEntry); err != nil {
+		t.Fatalf("failed to parse log output: %v", err)
+	}
+
+	if logEntry["tenant.id"] != "acme" {
+		t.Errorf("expected tenant.id acme, got %v", logEntry["tenant.id"])
+	}
+	if _, ok := logEntry["user.id"]; ok {
+		t.Error("expected missing baggage key to be skipped")
+	}
+	if _, ok := logEntry["session"]; ok {
+		t.Error("expected unselected baggage key to be skipped")
+	}
+	if logEntry["trace_id"] != "trace-789" {
+		t.Errorf("expected trace_id trace-789, got %v", logEntry["trace_id"])
+	}
+
+	// the original logger is left untouched
+	buf.Reset()
+	logger.Info().Msg("without baggage")
+	if bytes.Contains(buf.Bytes(), []byte("tenant.id")) {
+		t.Error("expected original logger to not include baggage")
+	}
+}
+
 func TestEventMethods(t *testing.T) {
 	var buf bytes.Buffer
 	traceID := "test-trace-789"
@@ -168,6 +213,42 @@ func TestEventMethods(t *testing.T) {
 		}
 	})
 
+	t.Run("Err method with structured error", func(t *testing.T) {
+		buf.Reset()
+		testErr := snackerrors.Wrap(errors.New("no rows"), snackerrors.CodeNotFound, "user not found")
+		testErr = snackerrors.WithMetadata(testErr, "user_id", "42")
+		logger.Error().Err(fmt.Errorf("get profile: %w", testErr)).Msg("error occurred")
+
+		var logEntry map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &logEntry); err != nil {
+			t.Fatalf("failed to parse log output: %v", err)
+		}
+
+		if logEntry["error"] != "get profile: user not found: no rows" {
+			t.Errorf("expected wrapped error message, got %v", logEntry["error"])
+		}
+		if logEntry["error_code"] != "not_found" {
+			t.Errorf("expected error_code not_found, got %v", logEntry["error_code"])
+		}
+		if logEntry["http_status"] != float64(404) {
+			t.Errorf("expected http_status 404, got %v", logEntry["http_status"])
+		}
+		if logEntry["grpc_code"] != "NotFound" {
+			t.Errorf("expected grpc_code NotFound, got %v", logEntry["grpc_code"])
+		}
+		metadata, ok := logEntry["error_metadata"].(map[string]interface{})
+		if !ok || metadata["user_id"] != "42" {
+			t.Errorf("expected error_metadata.user_id 42, got %v", logEntry["error_metadata"])
+		}
+		stack, ok := logEntry["error_stack"].([]interface{})
+		if !ok || len(stack) == 0 {
+			t.Fatalf("expected error_stack, got %v", logEntry["error_stack"])
+		}
+		if !strings.HasPrefix(stack[0].(string), "TestEventMethods.func") {
+			t.Errorf("expected stack to start in the test, got %v", stack[0])
+		}
+	})
+
 	t.Run("Str method", func(t *testing.T) {
 		buf.Reset()
 		logger.Info().Str("user", "john").Msg("user action")
@@ -0,0 +1,82 @@
+package logging
+
+import (
+	"context"
+
+	"github.com/bolanosdev/go-snacks/observability/sentry"
+	sentrygo "github.com/getsentry/sentry-go"
+	"github.com/rs/zerolog"
+)
+
+// sentryForwarder sends the entries of a ContextLogger to sentry
+type sentryForwarder struct {
+	ctx   context.Context
+	obs   sentry.SentryInterface
+	level zerolog.Level
+}
+
+// WithSentry returns a ContextLogger that also reports its entries to obs
+// with the hub of ctx, see SentryObs.WithHub. Entries at or above level are
+// captured as events, with the error passed to Err and the log fields as
+// extras, lower entries become breadcrumbs of the next event.
+func (cl *ContextLogger) WithSentry(ctx context.Context, obs sentry.SentryInterface, level zerolog.Level) *ContextLogger {
+	return &ContextLogger{
+		logger:  cl.logger,
+		traceID: cl.traceID,
+		fields:  cl.fields,
+		sentry:  &sentryForwarder{ctx: ctx, obs: obs, level: level},
+	}
+}
+
+// forward reports the entry once it was written
+func (e *Event) forward(msg string) {
+	f := e.sentry
+	if f == nil {
+		return
+	}
+
+	if e.level < f.level {
+		data := map[string]interface{}{"level": e.level.String()}
+		for key, val := range e.fields {
+			data[key] = val
+		}
+		if e.err != nil {
+			data["error"] = e.err.Error()
+		}
+		f.obs.AddBreadcrumb(f.ctx, "log", msg, data)
+		return
+	}
+
+	opts := []sentry.CaptureOption{sentry.WithLevel(sentryLevel(e.level))}
+	for key, val := range e.fields {
+		opts = append(opts, sentry.WithExtra(key, val))
+	}
+
+	if e.err != nil {
+		if msg != "" {
+			opts = append(opts, sentry.WithExtra("message", msg))
+		}
+		f.obs.CaptureError(f.ctx, e.err, opts...)
+		return
+	}
+
+	if msg == "" {
+		msg = e.level.String()
+	}
+	f.obs.CaptureMessage(f.ctx, msg, opts...)
+}
+
+func sentryLevel(level zerolog.Level) sentrygo.Level {
+	switch level {
+	case zerolog.TraceLevel, zerolog.DebugLevel:
+		return sentrygo.LevelDebug
+	case zerolog.InfoLevel:
+		return sentrygo.LevelInfo
+	case zerolog.WarnLevel:
+		return sentrygo.LevelWarning
+	case zerolog.ErrorLevel:
+		return sentrygo.LevelError
+	default:
+		return sentrygo.LevelFatal
+	}
+}
@@ -0,0 +1,83 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bolanosdev/go-snacks/observability/sentry/sentrytest"
+	sentrygo "github.com/getsentry/sentry-go"
+	"github.com/rs/zerolog"
+)
+
+func TestContextLoggerWithSentry(t *testing.T) {
+	var buf bytes.Buffer
+	recorder := sentrytest.NewRecorder(t)
+	ctx := recorder.WithHub(context.Background())
+
+	base := &ContextLogger{
+		logger:  zerolog.New(&buf),
+		traceID: "trace-123",
+	}
+	logger := base.WithSentry(ctx, recorder, zerolog.ErrorLevel)
+
+	logger.Debug().Str("cart", "c-1").Msg("loading cart")
+	logger.Warn().Int("attempt", 2).Msg("retrying payment")
+	logger.Error().Err(errors.New("card declined")).Int("order_id", 7).Msg("checkout failed")
+
+	if lines := strings.Count(buf.String(), "\n"); lines != 3 {
+		t.Errorf("expected 3 log lines, got %d", lines)
+	}
+
+	events := recorder.Events()
+	if len(events) != 1 {
+		t.Fatalf("expected 1 sentry event, got %d", len(events))
+	}
+
+	recorder.AssertLevel("card declined", sentrygo.LevelError)
+	recorder.AssertExtra("card declined", "order_id", 7)
+	recorder.AssertExtra("card declined", "trace_id", "trace-123")
+	recorder.AssertExtra("card declined", "message", "checkout failed")
+
+	breadcrumbs := events[0].Breadcrumbs
+	if len(breadcrumbs) != 2 {
+		t.Fatalf("expected 2 breadcrumbs, got %d", len(breadcrumbs))
+	}
+	if breadcrumbs[0].Message != "loading cart" || breadcrumbs[0].Data["level"] != "debug" || breadcrumbs[0].Data["cart"] != "c-1" {
+		t.Errorf("unexpected debug breadcrumb %+v", breadcrumbs[0])
+	}
+	if breadcrumbs[1].Message != "retrying payment" || breadcrumbs[1].Data["attempt"] != 2 {
+		t.Errorf("unexpected warn breadcrumb %+v", breadcrumbs[1])
+	}
+
+	// the original logger is not forwarded
+	base.Error().Err(errors.New("untracked")).Msg("not forwarded")
+	if len(recorder.Events()) != 1 {
+		t.Errorf("expected the base logger to skip sentry, got %d events", len(recorder.Events()))
+	}
+}
+
+func TestContextLoggerWithSentryLevel(t *testing.T) {
+	recorder := sentrytest.NewRecorder(t)
+	ctx := recorder.WithHub(context.Background())
+
+	logger := (&ContextLogger{logger: zerolog.New(&bytes.Buffer{}), traceID: "trace-456"}).
+		WithSentry(ctx, recorder, zerolog.WarnLevel)
+
+	logger.Info().Msg("started")
+	logger.Warn().Str("queue", "emails").Msgf("queue %s is backing up", "emails")
+
+	recorder.AssertLevel("queue emails is backing up", sentrygo.LevelWarning)
+	recorder.AssertExtra("queue emails is backing up", "queue", "emails")
+
+	// entries disabled by the logger level are not forwarded either
+	quiet := (&ContextLogger{logger: zerolog.New(&bytes.Buffer{}).Level(zerolog.ErrorLevel), traceID: "trace-456"}).
+		WithSentry(ctx, recorder, zerolog.WarnLevel)
+	quiet.Warn().Msg("dropped")
+
+	if len(recorder.Events()) != 1 {
+		t.Errorf("expected 1 sentry event, got %d", len(recorder.Events()))
+	}
+}
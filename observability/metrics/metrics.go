@@ -0,0 +1,204 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/bolanosdev/go-snacks/observability/internal/otelresource"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const defaultInterval = 15 * time.Second
+
+type MetricsConfig struct {
+	Name string
+	// Hostname of the OTLP collector, metrics are only pushed when it is set
+	Hostname string
+	// Interval between two OTLP pushes, defaults to 15 seconds
+	Interval time.Duration
+	// Prometheus exposes the metrics through Handler in the Prometheus text format
+	Prometheus bool
+	// ServiceVersion, Environment, InstanceID and ResourceAttributes describe
+	// the service like the jaeger.JaegerConfig fields of the same name
+	ServiceVersion     string
+	Environment        string
+	InstanceID         string
+	ResourceAttributes map[string]string
+	// RegisterGlobal makes Initialize install the meter provider as the otel
+	// global, otherwise it stays scoped to the returned MetricsObs
+	RegisterGlobal bool
+}
+
+// MetricsInterface defines the interface for metrics operations
+type MetricsInterface interface {
+	WithConfig(cfg MetricsConfig) MetricsInterface
+	Initialize() (MetricsInterface, error)
+	Handler() http.Handler
+	Counter(name, description string) (metric.Int64Counter, error)
+	UpDownCounter(name, description string) (metric.Int64UpDownCounter, error)
+	Histogram(name, description, unit string, buckets ...float64) (metric.Float64Histogram, error)
+	Gauge(name, description, unit string) (metric.Float64Gauge, error)
+	Shutdown(ctx context.Context) error
+}
+
+type MetricsObs struct {
+	cfg      MetricsConfig
+	ctx      context.Context
+	mp       metric.MeterProvider
+	sdk      *sdkmetric.MeterProvider
+	registry *prometheus.Registry
+	// conn is the collector connection created by Initialize, the exporter
+	// does not own it so Shutdown closes it
+	conn *grpc.ClientConn
+}
+
+func NewMetricsObs(ctx context.Context) MetricsObs {
+	return MetricsObs{
+		cfg: MetricsConfig{
+			Name:     "meter",
+			Hostname: "",
+			Interval: defaultInterval,
+		},
+		ctx: ctx,
+		mp:  otel.GetMeterProvider(),
+	}
+}
+
+func (m MetricsObs) WithConfig(cfg MetricsConfig) MetricsInterface {
+	m.cfg = cfg
+	return m
+}
+
+func (m MetricsObs) Initialize() (MetricsInterface, error) {
+	if m.cfg.Hostname == "" && !m.cfg.Prometheus {
+		return m, errors.New("missing metrics exporter, set a collector hostname or enable prometheus")
+	}
+
+	res, err := otelresource.New(m.ctx, otelresource.Config{
+		Name:        m.cfg.Name,
+		Version:     m.cfg.ServiceVersion,
+		Environment: m.cfg.Environment,
+		InstanceID:  m.cfg.InstanceID,
+		Attributes:  m.cfg.ResourceAttributes,
+	})
+	if err != nil {
+		return m, errors.Wrap(err, "failed to create resource for metrics")
+	}
+
+	opts := []sdkmetric.Option{sdkmetric.WithResource(res)}
+
+	if m.cfg.Hostname != "" {
+		conn, err := grpc.NewClient(m.cfg.Hostname,
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		)
+		if err != nil {
+			return m, errors.Wrap(err, "failed to create grpc connection for metrics")
+		}
+
+		ctx, cancel := context.WithTimeout(m.ctx, time.Second)
+		defer cancel()
+
+		exporter, err := otlpmetricgrpc.New(ctx, otlpmetricgrpc.WithGRPCConn(conn), otlpmetricgrpc.WithTimeout(1000*time.Millisecond))
+		if err != nil {
+			conn.Close()
+			return m, errors.Wrap(err, "failed to create otlp exporter for metrics")
+		}
+		m.conn = conn
+
+		interval := m.cfg.Interval
+		if interval <= 0 {
+			interval = defaultInterval
+		}
+		opts = append(opts, sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(interval))))
+	}
+
+	if m.cfg.Prometheus {
+		m.registry = prometheus.NewRegistry()
+
+		exporter, err := otelprom.New(otelprom.WithRegisterer(m.registry))
+		if err != nil {
+			if m.conn != nil {
+				m.conn.Close()
+			}
+			return m, errors.Wrap(err, "failed to create prometheus exporter for metrics")
+		}
+		opts = append(opts, sdkmetric.WithReader(exporter))
+	}
+
+	m.sdk = sdkmetric.NewMeterProvider(opts...)
+	m.mp = m.sdk
+
+	if m.cfg.RegisterGlobal {
+		otel.SetMeterProvider(m.sdk)
+	}
+
+	return m, nil
+}
+
+// Handler serves the collected metrics in the Prometheus text format, it
+// answers 404 unless MetricsConfig.Prometheus was enabled
+func (m MetricsObs) Handler() http.Handler {
+	if m.registry == nil {
+		return http.NotFoundHandler()
+	}
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Counter creates a monotonic int64 counter, e.g. requests served
+func (m MetricsObs) Counter(name, description string) (metric.Int64Counter, error) {
+	return m.meter().Int64Counter(name, metric.WithDescription(description))
+}
+
+// UpDownCounter creates an int64 counter that can go down, e.g. in-flight requests
+func (m MetricsObs) UpDownCounter(name, description string) (metric.Int64UpDownCounter, error) {
+	return m.meter().Int64UpDownCounter(name, metric.WithDescription(description))
+}
+
+// Histogram creates a float64 histogram, buckets override the default
+// explicit bucket boundaries when given
+func (m MetricsObs) Histogram(name, description, unit string, buckets ...float64) (metric.Float64Histogram, error) {
+	opts := []metric.Float64HistogramOption{
+		metric.WithDescription(description),
+		metric.WithUnit(unit),
+	}
+	if len(buckets) > 0 {
+		opts = append(opts, metric.WithExplicitBucketBoundaries(buckets...))
+	}
+
+	return m.meter().Float64Histogram(name, opts...)
+}
+
+// Gauge creates a float64 gauge recording the last value set, e.g. queue size
+func (m MetricsObs) Gauge(name, description, unit string) (metric.Float64Gauge, error) {
+	return m.meter().Float64Gauge(name, metric.WithDescription(description), metric.WithUnit(unit))
+}
+
+// Shutdown flushes pending metrics and stops the exporters
+func (m MetricsObs) Shutdown(ctx context.Context) error {
+	if m.sdk == nil {
+		return nil
+	}
+
+	err := m.sdk.Shutdown(ctx)
+	if m.conn != nil {
+		if closeErr := m.conn.Close(); closeErr != nil && err == nil {
+			err = errors.Wrap(closeErr, "failed to close metrics connection")
+		}
+	}
+	return err
+}
+
+func (m MetricsObs) meter() metric.Meter {
+	return m.mp.Meter(m.cfg.Name)
+}
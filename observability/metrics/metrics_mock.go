@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+type MockMetrics struct {
+	cfg MetricsConfig
+}
+
+func NewMockMetrics() MockMetrics {
+	return MockMetrics{}
+}
+
+func (m MockMetrics) WithConfig(cfg MetricsConfig) MetricsInterface {
+	m.cfg = cfg
+	return m
+}
+
+func (m MockMetrics) Initialize() (MetricsInterface, error) {
+	return m, nil
+}
+
+func (m MockMetrics) Handler() http.Handler {
+	return http.NotFoundHandler()
+}
+
+func (m MockMetrics) Counter(name, description string) (metric.Int64Counter, error) {
+	return noop.Meter{}.Int64Counter(name)
+}
+
+func (m MockMetrics) UpDownCounter(name, description string) (metric.Int64UpDownCounter, error) {
+	return noop.Meter{}.Int64UpDownCounter(name)
+}
+
+func (m MockMetrics) Histogram(name, description, unit string, buckets ...float64) (metric.Float64Histogram, error) {
+	return noop.Meter{}.Float64Histogram(name)
+}
+
+func (m MockMetrics) Gauge(name, description, unit string) (metric.Float64Gauge, error) {
+	return noop.Meter{}.Float64Gauge(name)
+}
+
+func (m MockMetrics) Shutdown(ctx context.Context) error {
+	return nil
+}
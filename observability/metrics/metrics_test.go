@@ -0,0 +1,149 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+func TestInitializeRequiresAnExporter(t *testing.T) {
+	_, err := NewMetricsObs(context.Background()).
+		WithConfig(MetricsConfig{Name: "svc"}).
+		Initialize()
+	require.Error(t, err)
+}
+
+// newCollector starts a gRPC server without the metrics service, connections
+// succeed but every export fails with Unimplemented
+func newCollector(t *testing.T) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	srv := grpc.NewServer()
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	return lis.Addr().String()
+}
+
+func TestShutdownClosesConnection(t *testing.T) {
+	ctx := context.Background()
+
+	obs, err := NewMetricsObs(ctx).
+		WithConfig(MetricsConfig{Name: "svc", Hostname: newCollector(t)}).
+		Initialize()
+	require.NoError(t, err)
+
+	conn := obs.(MetricsObs).conn
+	require.NotNil(t, conn)
+
+	// the final export fails against the fake collector, only the connection matters
+	_ = obs.Shutdown(ctx)
+	require.Equal(t, connectivity.Shutdown, conn.GetState())
+}
+
+func TestPrometheusHandler(t *testing.T) {
+	ctx := context.Background()
+
+	obs, err := NewMetricsObs(ctx).
+		WithConfig(MetricsConfig{Name: "svc", Prometheus: true}).
+		Initialize()
+	require.NoError(t, err)
+	defer obs.Shutdown(ctx)
+
+	counter, err := obs.Counter("jobs_processed", "processed jobs")
+	require.NoError(t, err)
+	counter.Add(ctx, 3, metric.WithAttributes(attribute.String("queue", "emails")))
+
+	histogram, err := obs.Histogram("job_duration", "job duration", "s", 0.1, 1, 10)
+	require.NoError(t, err)
+	histogram.Record(ctx, 0.5)
+
+	gauge, err := obs.Gauge("queue_size", "pending jobs", "")
+	require.NoError(t, err)
+	gauge.Record(ctx, 7)
+
+	srv := httptest.NewServer(obs.Handler())
+	defer srv.Close()
+
+	res, err := http.Get(srv.URL)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	require.Contains(t, string(body), `jobs_processed_total{otel_scope_name="svc",otel_scope_schema_url="",otel_scope_version="",queue="emails"} 3`)
+	require.Contains(t, string(body), `job_duration_seconds_bucket{otel_scope_name="svc",otel_scope_schema_url="",otel_scope_version="",le="1"} 1`)
+	require.Contains(t, string(body), `queue_size{otel_scope_name="svc",otel_scope_schema_url="",otel_scope_version=""} 7`)
+	require.Contains(t, string(body), `service_name="svc"`)
+}
+
+func TestHandlerWithoutPrometheus(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewMetricsObs(context.Background()).Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestInitializeRegisterGlobal(t *testing.T) {
+	global := otel.GetMeterProvider()
+	t.Cleanup(func() { otel.SetMeterProvider(global) })
+
+	ctx := context.Background()
+
+	scoped, err := NewMetricsObs(ctx).
+		WithConfig(MetricsConfig{Name: "svc", Prometheus: true}).
+		Initialize()
+	require.NoError(t, err)
+	defer scoped.Shutdown(ctx)
+	require.NotSame(t, scoped.(MetricsObs).sdk, otel.GetMeterProvider())
+
+	registered, err := NewMetricsObs(ctx).
+		WithConfig(MetricsConfig{Name: "svc", Prometheus: true, RegisterGlobal: true}).
+		Initialize()
+	require.NoError(t, err)
+	defer registered.Shutdown(ctx)
+	require.Same(t, registered.(MetricsObs).sdk, otel.GetMeterProvider())
+}
+
+func TestPrometheusHandlerReportsResource(t *testing.T) {
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "team=payments")
+	ctx := context.Background()
+
+	obs, err := NewMetricsObs(ctx).
+		WithConfig(MetricsConfig{
+			Name:               "svc",
+			Prometheus:         true,
+			ServiceVersion:     "1.4.0",
+			Environment:        "production",
+			InstanceID:         "svc-1",
+			ResourceAttributes: map[string]string{"region": "eu-west-1"},
+		}).
+		Initialize()
+	require.NoError(t, err)
+	defer obs.Shutdown(ctx)
+
+	rec := httptest.NewRecorder()
+	obs.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	for _, label := range []string{
+		`service_name="svc"`,
+		`service_version="1.4.0"`,
+		`deployment_environment="production"`,
+		`service_instance_id="svc-1"`,
+		`region="eu-west-1"`,
+		`team="payments"`,
+	} {
+		require.Contains(t, rec.Body.String(), label)
+	}
+}
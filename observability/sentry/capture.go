@@ -0,0 +1,171 @@
+package sentry
+
+import (
+	"context"
+	"time"
+
+	snackerrors "github.com/bolanosdev/go-snacks/errors"
+	"github.com/getsentry/sentry-go"
+	"github.com/pkg/errors"
+)
+
+// CaptureOption customizes a single event reported by CaptureError or
+// CaptureMessage, it only changes the scope of that event
+type CaptureOption func(scope *sentry.Scope)
+
+// WithLevel overrides the level, errors default to LevelError and messages
+// to LevelInfo
+func WithLevel(level sentry.Level) CaptureOption {
+	return func(scope *sentry.Scope) {
+		scope.SetLevel(level)
+	}
+}
+
+// WithStatusCode records the HTTP or RPC status code as the status_code extra
+func WithStatusCode(status_code int) CaptureOption {
+	return WithExtra("status_code", status_code)
+}
+
+func WithTag(key, value string) CaptureOption {
+	return func(scope *sentry.Scope) {
+		scope.SetTag(key, value)
+	}
+}
+
+func WithTags(tags map[string]string) CaptureOption {
+	return func(scope *sentry.Scope) {
+		scope.SetTags(tags)
+	}
+}
+
+func WithExtra(key string, value interface{}) CaptureOption {
+	return func(scope *sentry.Scope) {
+		scope.SetExtra(key, value)
+	}
+}
+
+func WithUser(user sentry.User) CaptureOption {
+	return func(scope *sentry.Scope) {
+		scope.SetUser(user)
+	}
+}
+
+// WithFingerprint groups the event into the issue identified by fingerprint
+// instead of the stack trace, use "{{ default }}" to refine the default
+// grouping
+func WithFingerprint(fingerprint ...string) CaptureOption {
+	return func(scope *sentry.Scope) {
+		scope.SetFingerprint(fingerprint)
+	}
+}
+
+// WithContext adds a structured context shown in its own section of the event
+func WithContext(key string, value sentry.Context) CaptureOption {
+	return func(scope *sentry.Scope) {
+		scope.SetContext(key, value)
+	}
+}
+
+// WithAttachment uploads payload as a file alongside the event
+func WithAttachment(filename, content_type string, payload []byte) CaptureOption {
+	return func(scope *sentry.Scope) {
+		scope.AddAttachment(&sentry.Attachment{
+			Filename:    filename,
+			ContentType: content_type,
+			Payload:     payload,
+		})
+	}
+}
+
+// CaptureError reports err with the hub of ctx, see WithHub. The event is
+// tagged with the trace_id and span_id of the span in ctx and the returned
+// event ID can be written to the matching log entry. The GetMetadata() map
+// of err is added as extras and a structured error from the errors package
+// also sets the error.code tag, status_code and grpc_code.
+func (s *SentryObs) CaptureError(ctx context.Context, err error, opts ...CaptureOption) *sentry.EventID {
+	opts = append(append(errorOptions(err), metadataOptions(err)...), opts...)
+
+	return s.capture(ctx, sentry.LevelError, opts, func(hub *sentry.Hub, scope *sentry.Scope) *sentry.EventID {
+		return hub.CaptureException(err)
+	})
+}
+
+// metadataOptions adds the GetMetadata() map of err as extras, before the
+// caller options so a WithExtra of the same key wins
+func metadataOptions(err error) []CaptureOption {
+	type metadataError interface {
+		GetMetadata() map[string]interface{}
+	}
+
+	var mdErr metadataError
+	if !errors.As(err, &mdErr) {
+		return nil
+	}
+
+	var opts []CaptureOption
+	for key, value := range mdErr.GetMetadata() {
+		opts = append(opts, WithExtra(key, value))
+	}
+	return opts
+}
+
+// errorOptions reports the code and statuses of a structured error, before
+// the caller options so they can still override them
+func errorOptions(err error) []CaptureOption {
+	var e *snackerrors.Error
+	if !errors.As(err, &e) {
+		return nil
+	}
+
+	return []CaptureOption{
+		WithTag("error.code", string(e.Code())),
+		WithStatusCode(e.HTTPStatus()),
+		WithExtra("grpc_code", e.GRPCCode().String()),
+	}
+}
+
+// CaptureMessage reports msg, for warnings and business events that are not
+// errors. Use WithFingerprint to group messages containing variable data.
+func (s *SentryObs) CaptureMessage(ctx context.Context, msg string, opts ...CaptureOption) *sentry.EventID {
+	return s.capture(ctx, sentry.LevelInfo, opts, func(hub *sentry.Hub, scope *sentry.Scope) *sentry.EventID {
+		return hub.CaptureMessage(msg)
+	})
+}
+
+// AddBreadcrumb records a step leading to the events later captured with
+// ctx, it is a no-op without a hub in ctx so breadcrumbs never leak between
+// requests
+func (s *SentryObs) AddBreadcrumb(ctx context.Context, category, message string, data map[string]interface{}) {
+	hub := sentry.GetHubFromContext(ctx)
+	if hub == nil {
+		return
+	}
+
+	hub.AddBreadcrumb(&sentry.Breadcrumb{
+		Category:  category,
+		Message:   message,
+		Data:      data,
+		Level:     sentry.LevelInfo,
+		Timestamp: time.Now(),
+	}, nil)
+}
+
+// capture sends the event built by send on a scope holding level, the trace
+// of ctx and opts, options are applied last so they win
+func (s *SentryObs) capture(ctx context.Context, level sentry.Level, opts []CaptureOption, send func(hub *sentry.Hub, scope *sentry.Scope) *sentry.EventID) *sentry.EventID {
+	var event_id *sentry.EventID
+
+	hub := s.Hub(ctx)
+	hub.WithScope(func(scope *sentry.Scope) {
+		scope.SetLevel(level)
+		linkTrace(ctx, scope)
+
+		for _, opt := range opts {
+			opt(scope)
+		}
+
+		event_id = send(hub, scope)
+	})
+
+	return event_id
+}
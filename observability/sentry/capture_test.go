@@ -0,0 +1,123 @@
+package sentry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	snackerrors "github.com/bolanosdev/go-snacks/errors"
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/require"
+)
+
+type metadataError struct {
+	error
+	metadata map[string]interface{}
+}
+
+func (e metadataError) GetMetadata() map[string]interface{} {
+	return e.metadata
+}
+
+func TestCaptureErrorOptions(t *testing.T) {
+	obs, transport := newTestObs(t)
+	ctx := obs.WithHub(context.Background())
+
+	err := metadataError{error: errors.New("card declined"), metadata: map[string]interface{}{"order_id": 7, "provider": "adyen"}}
+	event_id := obs.CaptureError(ctx, err,
+		WithLevel(sentry.LevelWarning),
+		WithStatusCode(402),
+		WithTag("provider", "stripe"),
+		WithExtra("provider", "stripe"),
+		WithTags(map[string]string{"tenant": "acme"}),
+		WithUser(sentry.User{ID: "42"}),
+		WithFingerprint("payments", "card-declined"),
+		WithContext("payment", sentry.Context{"amount": 10}),
+		WithAttachment("response.json", "application/json", []byte(`{"code":"declined"}`)),
+	)
+	require.NotNil(t, event_id)
+
+	events := transport.Events()
+	require.Len(t, events, 1)
+
+	event := events[0]
+	require.Equal(t, sentry.LevelWarning, event.Level)
+	require.Equal(t, 402, event.Extra["status_code"])
+	require.Equal(t, 7, event.Extra["order_id"])
+	// options win over the error metadata
+	require.Equal(t, "stripe", event.Extra["provider"])
+	require.Equal(t, map[string]string{"provider": "stripe", "tenant": "acme"}, event.Tags)
+	require.Equal(t, "42", event.User.ID)
+	require.Equal(t, []string{"payments", "card-declined"}, event.Fingerprint)
+	require.Equal(t, 10, event.Contexts["payment"]["amount"])
+	require.Len(t, event.Attachments, 1)
+	require.Equal(t, "response.json", event.Attachments[0].Filename)
+
+	// options only apply to their own event
+	obs.CaptureError(ctx, errors.New("other"))
+	event = transport.Events()[1]
+	require.Equal(t, sentry.LevelError, event.Level)
+	require.Empty(t, event.Tags)
+	require.Empty(t, event.Fingerprint)
+	require.Empty(t, event.Attachments)
+}
+
+func TestCaptureStructuredError(t *testing.T) {
+	obs, transport := newTestObs(t)
+	ctx := obs.WithHub(context.Background())
+
+	err := snackerrors.Wrap(errors.New("no rows"), snackerrors.CodeNotFound, "user not found")
+	err = snackerrors.WithMetadata(err, "user_id", 42)
+	obs.CaptureError(ctx, fmt.Errorf("get profile: %w", err))
+	obs.CaptureError(ctx, err, WithStatusCode(410))
+
+	events := transport.Events()
+	require.Len(t, events, 2)
+
+	event := events[0]
+	require.Equal(t, "not_found", event.Tags["error.code"])
+	require.Equal(t, 404, event.Extra["status_code"])
+	require.Equal(t, "NotFound", event.Extra["grpc_code"])
+	require.Equal(t, 42, event.Extra["user_id"])
+
+	// the stack of the structured error is reported, not the capture site
+	var stacktrace *sentry.Stacktrace
+	for _, exception := range event.Exception {
+		if exception.Value == "user not found: no rows" {
+			stacktrace = exception.Stacktrace
+		}
+	}
+	require.NotNil(t, stacktrace)
+	require.Equal(t, "TestCaptureStructuredError", stacktrace.Frames[len(stacktrace.Frames)-1].Function)
+
+	// caller options win
+	require.Equal(t, 410, events[1].Extra["status_code"])
+}
+
+func TestCaptureMessageAndBreadcrumbs(t *testing.T) {
+	obs, transport := newTestObs(t)
+
+	// without a request hub breadcrumbs are dropped
+	obs.AddBreadcrumb(context.Background(), "auth", "ignored", nil)
+
+	ctx := obs.WithHub(context.Background())
+	obs.AddBreadcrumb(ctx, "cart", "item added", map[string]interface{}{"sku": "A1"})
+	obs.AddBreadcrumb(ctx, "checkout", "payment started", nil)
+
+	require.NotNil(t, obs.CaptureMessage(ctx, "checkout abandoned", WithFingerprint("checkout-abandoned")))
+	require.NotNil(t, obs.CaptureMessage(context.Background(), "quota low", WithLevel(sentry.LevelWarning)))
+
+	events := transport.Events()
+	require.Len(t, events, 2)
+
+	require.Equal(t, "checkout abandoned", events[0].Message)
+	require.Equal(t, sentry.LevelInfo, events[0].Level)
+	require.Equal(t, []string{"checkout-abandoned"}, events[0].Fingerprint)
+	require.Len(t, events[0].Breadcrumbs, 2)
+	require.Equal(t, "cart", events[0].Breadcrumbs[0].Category)
+	require.Equal(t, "A1", events[0].Breadcrumbs[0].Data["sku"])
+
+	require.Equal(t, sentry.LevelWarning, events[1].Level)
+	require.Empty(t, events[1].Breadcrumbs)
+}
@@ -0,0 +1,175 @@
+package sentry
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bolanosdev/go-snacks/observability/internal/response"
+	"github.com/getsentry/sentry-go"
+	"github.com/pkg/errors"
+)
+
+const defaultFlushTimeout = 2 * time.Second
+
+// sensitiveHeaders are dropped from events without SendDefaultPII, the same
+// list sentry-go filters in sentry.NewRequest
+var sensitiveHeaders = map[string]struct{}{
+	"_csrf":               {},
+	"_csrf_token":         {},
+	"_session":            {},
+	"_xsrf":               {},
+	"Api-Key":             {},
+	"Apikey":              {},
+	"Auth":                {},
+	"Authorization":       {},
+	"Cookie":              {},
+	"Credentials":         {},
+	"Csrf":                {},
+	"Csrf-Token":          {},
+	"Csrftoken":           {},
+	"Ip-Address":          {},
+	"Passwd":              {},
+	"Password":            {},
+	"Private-Key":         {},
+	"Privatekey":          {},
+	"Proxy-Authorization": {},
+	"Remote-Addr":         {},
+	"Secret":              {},
+	"Session":             {},
+	"Sessionid":           {},
+	"Token":               {},
+	"User-Session":        {},
+	"X-Api-Key":           {},
+	"X-Csrftoken":         {},
+	"X-Forwarded-For":     {},
+	"X-Real-Ip":           {},
+	"Xsrf-Token":          {},
+}
+
+// MiddlewareOption customizes HTTPMiddleware
+type MiddlewareOption func(*middlewareConfig)
+
+type middlewareConfig struct {
+	repanic      bool
+	flushTimeout time.Duration
+}
+
+// WithRepanic panics again once the panic was reported and flushed, so an
+// outer recovery or the http.Server still sees it
+func WithRepanic() MiddlewareOption {
+	return func(c *middlewareConfig) {
+		c.repanic = true
+	}
+}
+
+// WithFlushTimeout bounds how long a panic report is waited for before
+// repanicking, defaults to 2 seconds
+func WithFlushTimeout(d time.Duration) MiddlewareOption {
+	return func(c *middlewareConfig) {
+		c.flushTimeout = d
+	}
+}
+
+// HTTPMiddleware gives every request its own hub, see WithHub, recovers
+// panics into a plain 500 response and captures 5xx responses. Events
+// carry the method, URL and headers of the request, masked before sending
+// like the rest of the event.
+func (s *SentryObs) HTTPMiddleware(opts ...MiddlewareOption) func(http.Handler) http.Handler {
+	cfg := middlewareConfig{flushTimeout: defaultFlushTimeout}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := s.WithHub(r.Context())
+			hub := s.Hub(ctx)
+
+			request := newRequest(r, s.cfg.SendDefaultPII)
+			hub.Scope().AddEventProcessor(func(event *sentry.Event, hint *sentry.EventHint) *sentry.Event {
+				if event.Request == nil {
+					event.Request = request
+				}
+				return event
+			})
+
+			rec := response.NewRecorder(w)
+			req := r.WithContext(ctx)
+
+			defer func() {
+				err := recover()
+				if err == nil {
+					return
+				}
+				// the handler asked to abort the response, not a bug
+				if err == http.ErrAbortHandler {
+					panic(err)
+				}
+
+				s.recoverPanic(ctx, err)
+
+				if cfg.repanic {
+					hub.Flush(cfg.flushTimeout)
+					panic(err)
+				}
+				if !rec.WroteHeader {
+					http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				}
+			}()
+
+			next.ServeHTTP(rec, req)
+
+			if rec.Status >= http.StatusInternalServerError {
+				route := req.Pattern
+				if route == "" {
+					route = req.Method + " " + req.URL.Path
+				}
+				// the error is always built here, the route and status tell the
+				// issues apart
+				s.CaptureError(ctx, errors.Errorf("%s responded %d %s", route, rec.Status, http.StatusText(rec.Status)),
+					WithStatusCode(rec.Status),
+					WithFingerprint("{{ default }}", route, strconv.Itoa(rec.Status)))
+			}
+		})
+	}
+}
+
+func (s *SentryObs) recoverPanic(ctx context.Context, err any) {
+	s.capture(ctx, sentry.LevelFatal, []CaptureOption{WithStatusCode(http.StatusInternalServerError)}, func(hub *sentry.Hub, scope *sentry.Scope) *sentry.EventID {
+		return hub.RecoverWithContext(ctx, err)
+	})
+}
+
+// newRequest builds the request data of events, cookies and the client IP
+// or credential headers are only sent with PII enabled and the scrubber masks
+// sensitive headers and parameters
+func newRequest(r *http.Request, pii bool) *sentry.Request {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+
+	headers := make(map[string]string, len(r.Header)+1)
+	for name, values := range r.Header {
+		if _, ok := sensitiveHeaders[http.CanonicalHeaderKey(name)]; ok && !pii {
+			continue
+		}
+		headers[name] = strings.Join(values, ",")
+	}
+	headers["Host"] = r.Host
+
+	request := &sentry.Request{
+		URL:         scheme + "://" + r.Host + r.URL.Path,
+		Method:      r.Method,
+		QueryString: r.URL.RawQuery,
+		Headers:     headers,
+	}
+	if pii {
+		request.Cookies = r.Header.Get("Cookie")
+	}
+
+	return request
+}
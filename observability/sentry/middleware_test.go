@@ -0,0 +1,141 @@
+package sentry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPMiddlewareRecoversPanics(t *testing.T) {
+	obs, transport := newTestObs(t)
+
+	handler := obs.HTTPMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		obs.SetTag(r.Context(), "tenant", "acme")
+		panic("boom")
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/users?token=abc&page=2", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("Cookie", "session=abc")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+	req.Header.Set("X-Real-Ip", "203.0.113.7")
+	req.Header.Set("X-Request-Token", "abc")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	require.Equal(t, "Internal Server Error\n", rec.Body.String())
+
+	events := transport.Events()
+	require.Len(t, events, 1)
+
+	event := events[0]
+	require.Equal(t, sentry.LevelFatal, event.Level)
+	require.Equal(t, "boom", event.Message)
+	require.Equal(t, "acme", event.Tags["tenant"])
+	require.Equal(t, http.StatusInternalServerError, event.Extra["status_code"])
+
+	require.Equal(t, http.MethodPost, event.Request.Method)
+	require.Equal(t, "http://example.com/users", event.Request.URL)
+	require.Equal(t, "page=2&token=%2A%2A%2A", event.Request.QueryString)
+	// without PII the credential and client IP headers are dropped
+	require.NotContains(t, event.Request.Headers, "Authorization")
+	require.NotContains(t, event.Request.Headers, "Cookie")
+	require.NotContains(t, event.Request.Headers, "X-Forwarded-For")
+	require.NotContains(t, event.Request.Headers, "X-Real-Ip")
+	require.Equal(t, "***", event.Request.Headers["X-Request-Token"])
+	require.Equal(t, "application/json", event.Request.Headers["Accept"])
+	require.Empty(t, event.Request.Cookies)
+}
+
+func TestHTTPMiddlewareRepanic(t *testing.T) {
+	obs, transport := newTestObs(t)
+
+	handler := obs.HTTPMiddleware(WithRepanic())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	require.PanicsWithValue(t, "boom", func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+	require.Len(t, transport.Events(), 1)
+}
+
+func TestHTTPMiddlewareSkipsAbortHandler(t *testing.T) {
+	obs, transport := newTestObs(t)
+
+	handler := obs.HTTPMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	require.Panics(t, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+	require.Empty(t, transport.Events())
+}
+
+func TestHTTPMiddlewareCapturesServerErrors(t *testing.T) {
+	obs, transport := newTestObs(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	mux.HandleFunc("GET /missing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("GET /orders", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	handler := obs.HTTPMiddleware()(mux)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+	require.Equal(t, http.StatusBadGateway, rec.Code)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders", nil))
+
+	events := transport.Events()
+	require.Len(t, events, 2)
+	require.Equal(t, sentry.LevelError, events[0].Level)
+	require.Equal(t, "GET /users/{id} responded 502 Bad Gateway", events[0].Exception[0].Value)
+	require.Equal(t, []string{"{{ default }}", "GET /users/{id}", "502"}, events[0].Fingerprint)
+	require.Equal(t, []string{"{{ default }}", "GET /orders", "503"}, events[1].Fingerprint)
+	require.Equal(t, http.StatusBadGateway, events[0].Extra["status_code"])
+	require.Equal(t, "http://example.com/users/42", events[0].Request.URL)
+}
+
+func TestHTTPMiddlewareKeepsFlusherAndHijacker(t *testing.T) {
+	obs, transport := newTestObs(t)
+
+	handler := obs.HTTPMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, ok := w.(http.Hijacker)
+		require.True(t, ok)
+
+		w.(http.Flusher).Flush()
+		panic("boom")
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stream", nil))
+
+	// the flush committed the headers, the recovery does not write again
+	require.True(t, rec.Flushed)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Empty(t, rec.Body.String())
+	require.Len(t, transport.Events(), 1)
+
+	// httptest.ResponseRecorder can't be hijacked
+	handler = obs.HTTPMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _, err := w.(http.Hijacker).Hijack()
+		require.ErrorIs(t, err, http.ErrNotSupported)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ws", nil))
+}
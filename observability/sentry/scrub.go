@@ -0,0 +1,232 @@
+package sentry
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/bolanosdev/go-snacks/observability/internal/mask"
+	"github.com/getsentry/sentry-go"
+	"github.com/pkg/errors"
+)
+
+// defaultSensitiveKeywords are always masked, SentryConfig.SensitiveKeywords
+// adds to them. Keys match by substring so the names are kept specific,
+// "key" or "auth" alone would mask monkey or author.
+var defaultSensitiveKeywords = []string{
+	"authorization", "cookie", "token", "secret", "password", "passwd", "session",
+	"api_key", "api-key", "apikey", "private_key", "private-key", "csrf", "xsrf",
+}
+
+// scrubber is the BeforeSend hook masking sensitive data out of events and
+// dropping the events of ignored error types
+type scrubber struct {
+	mask       *mask.Masker
+	messages   bool
+	dropErrors []error
+	dropTypes  []reflect.Type
+	next       func(event *sentry.Event, hint *sentry.EventHint) *sentry.Event
+}
+
+func newScrubber(cfg SentryConfig) *scrubber {
+	keywords := append(append([]string{}, defaultSensitiveKeywords...), cfg.SensitiveKeywords...)
+
+	s := &scrubber{
+		mask:       mask.New(keywords, cfg.SensitivePatterns),
+		messages:   cfg.ScrubMessages,
+		dropErrors: cfg.DropErrors,
+		next:       cfg.BeforeSend,
+	}
+	for _, err := range cfg.DropErrorTypes {
+		if err != nil {
+			s.dropTypes = append(s.dropTypes, reflect.TypeOf(err))
+		}
+	}
+
+	return s
+}
+
+func (s *scrubber) beforeSend(event *sentry.Event, hint *sentry.EventHint) *sentry.Event {
+	if s.drop(hint) {
+		return nil
+	}
+
+	event.Message = s.text(event.Message)
+	for i := range event.Exception {
+		event.Exception[i].Value = s.text(event.Exception[i].Value)
+	}
+
+	for key, value := range event.Tags {
+		event.Tags[key] = s.string(key, value)
+	}
+	event.Extra = s.fields(event.Extra)
+
+	for key, value := range event.Contexts {
+		// the trace context holds ids only, masking would mangle them
+		if key == "trace" {
+			continue
+		}
+		event.Contexts[key] = s.fields(value)
+	}
+
+	if event.Request != nil {
+		s.request(event.Request)
+	}
+
+	for _, breadcrumb := range event.Breadcrumbs {
+		breadcrumb.Message = s.text(breadcrumb.Message)
+		breadcrumb.Data = s.fields(breadcrumb.Data)
+	}
+
+	if s.next != nil {
+		return s.next(event, hint)
+	}
+	return event
+}
+
+// beforeSendTransaction masks the transactions built from spans, their tags,
+// contexts and span data hold the raw span attributes
+func (s *scrubber) beforeSendTransaction(event *sentry.Event, hint *sentry.EventHint) *sentry.Event {
+	for key, value := range event.Tags {
+		event.Tags[key] = s.string(key, value)
+	}
+	event.Extra = s.fields(event.Extra)
+
+	for key, value := range event.Contexts {
+		if key == "trace" {
+			continue
+		}
+		event.Contexts[key] = s.fields(value)
+	}
+
+	if event.Request != nil {
+		s.request(event.Request)
+	}
+
+	for _, span := range event.Spans {
+		span.Description = s.text(span.Description)
+		for key, value := range span.Tags {
+			span.Tags[key] = s.string(key, value)
+		}
+		span.Data = s.fields(span.Data)
+	}
+
+	return event
+}
+
+// drop reports whether the error of the event is ignored
+func (s *scrubber) drop(hint *sentry.EventHint) bool {
+	if hint == nil {
+		return false
+	}
+
+	err, ok := hint.OriginalException.(error)
+	if !ok {
+		err, ok = hint.RecoveredException.(error)
+	}
+	if !ok || err == nil {
+		return false
+	}
+
+	for _, target := range s.dropErrors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	for current := err; current != nil; current = errors.Unwrap(current) {
+		for _, t := range s.dropTypes {
+			if reflect.TypeOf(current) == t {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// text masks messages, keyword=value pairs only with ScrubMessages since
+// ordinary errors often mention a keyword
+func (s *scrubber) text(value string) string {
+	if s.messages {
+		return s.mask.String(value)
+	}
+	return s.mask.Patterns(value)
+}
+
+func (s *scrubber) string(key, value string) string {
+	if s.mask.SensitiveKey(key) {
+		return mask.Masked
+	}
+	return s.mask.String(value)
+}
+
+func (s *scrubber) fields(fields map[string]interface{}) map[string]interface{} {
+	if fields == nil {
+		return nil
+	}
+
+	masked, _ := s.mask.Value(fields).(map[string]any)
+	return masked
+}
+
+func (s *scrubber) request(request *sentry.Request) {
+	for name, value := range request.Headers {
+		request.Headers[name] = s.string(name, value)
+	}
+
+	request.QueryString = s.query(request.QueryString)
+	request.Cookies = s.cookies(request.Cookies)
+	request.Data = s.body(request.Data)
+}
+
+func (s *scrubber) query(raw string) string {
+	if raw == "" {
+		return ""
+	}
+
+	query, err := url.ParseQuery(raw)
+	if err != nil {
+		// unparsable queries may hide anything
+		return ""
+	}
+
+	for name, values := range query {
+		for i, value := range values {
+			values[i] = s.string(name, value)
+		}
+	}
+	return query.Encode()
+}
+
+func (s *scrubber) cookies(raw string) string {
+	if raw == "" {
+		return ""
+	}
+
+	cookies := strings.Split(raw, ";")
+	for i, cookie := range cookies {
+		name, value, _ := strings.Cut(strings.TrimSpace(cookie), "=")
+		cookies[i] = name + "=" + s.string(name, value)
+	}
+	return strings.Join(cookies, "; ")
+}
+
+// body masks JSON bodies field by field and any other body as text
+func (s *scrubber) body(data string) string {
+	if data == "" {
+		return ""
+	}
+
+	var decoded any
+	if err := json.Unmarshal([]byte(data), &decoded); err != nil {
+		return s.mask.String(data)
+	}
+
+	encoded, err := json.Marshal(s.mask.Value(decoded))
+	if err != nil {
+		return mask.Masked
+	}
+	return string(encoded)
+}
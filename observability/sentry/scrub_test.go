@@ -0,0 +1,150 @@
+package sentry
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/require"
+)
+
+type credentials struct {
+	User string `json:"user"`
+	PIN  string `json:"pin" mask:"true"`
+}
+
+func TestScrubberMasksEvents(t *testing.T) {
+	obs, transport := newTestObsWithConfig(t, SentryConfig{
+		SensitiveKeywords: []string{"iban"},
+		SensitivePatterns: []*regexp.Regexp{regexp.MustCompile(`\d{4}-\d{4}`)},
+		ScrubMessages:     true,
+	})
+
+	ctx := obs.WithHub(context.Background())
+	obs.AddBreadcrumb(ctx, "auth", "login with password=hunter2", map[string]interface{}{
+		"token": "abc",
+		"step":  "mfa",
+	})
+
+	obs.CaptureError(ctx, metadataError{
+		error: errors.New("charge failed for card 1234-5678"),
+		metadata: map[string]interface{}{
+			"iban":        "DE89",
+			"credentials": credentials{User: "john", PIN: "1234"},
+			"attempts":    3,
+		},
+	},
+		WithTag("api_key", "abc"),
+		WithTag("tenant", "acme"),
+		WithContext("payment", sentry.Context{"secret": "s", "amount": 10}),
+	)
+
+	events := transport.Events()
+	require.Len(t, events, 1)
+
+	event := events[0]
+	require.Equal(t, "charge failed for card ***", event.Exception[0].Value)
+	require.Equal(t, "***", event.Tags["api_key"])
+	require.Equal(t, "acme", event.Tags["tenant"])
+	require.Equal(t, "***", event.Extra["iban"])
+	require.Equal(t, map[string]any{"user": "john", "pin": "***"}, event.Extra["credentials"])
+	require.Equal(t, 3, event.Extra["attempts"])
+	require.Equal(t, "***", event.Contexts["payment"]["secret"])
+	require.Equal(t, 10, event.Contexts["payment"]["amount"])
+	require.NotEmpty(t, event.Contexts["trace"]["trace_id"])
+
+	require.Len(t, event.Breadcrumbs, 1)
+	require.Equal(t, "login with password=***", event.Breadcrumbs[0].Message)
+	require.Equal(t, map[string]any{"token": "***", "step": "mfa"}, event.Breadcrumbs[0].Data)
+}
+
+func TestScrubberKeepsMessages(t *testing.T) {
+	obs, transport := newTestObs(t)
+	ctx := obs.WithHub(context.Background())
+
+	messages := []string{
+		`pq: duplicate key value violates unique constraint "users_email_key"`,
+		"failed to authenticate user bob",
+		"monkey patch applied to author module",
+		"login with password=hunter2",
+	}
+	for _, msg := range messages {
+		obs.AddBreadcrumb(ctx, "log", msg, map[string]interface{}{"author": "bob", "monkey": "patch"})
+		obs.CaptureError(ctx, errors.New(msg))
+	}
+
+	// keyword=value masking of free text is opt-in through ScrubMessages
+	events := transport.Events()
+	require.Len(t, events, len(messages))
+	for i, event := range events {
+		require.Equal(t, messages[i], event.Exception[0].Value)
+		require.Equal(t, messages[i], event.Breadcrumbs[len(event.Breadcrumbs)-1].Message)
+		require.Equal(t, map[string]any{"author": "bob", "monkey": "patch"}, event.Breadcrumbs[0].Data)
+	}
+}
+
+func TestScrubberMasksRequests(t *testing.T) {
+	obs, transport := newTestObsWithConfig(t, SentryConfig{SendDefaultPII: true})
+
+	ctx := obs.WithHub(context.Background())
+	obs.ConfigureScope(ctx, func(scope *sentry.Scope) {
+		scope.AddEventProcessor(func(event *sentry.Event, hint *sentry.EventHint) *sentry.Event {
+			event.Request = &sentry.Request{
+				Data:        `{"email":"a@b.c","password":"hunter2","nested":{"refresh_token":"r"}}`,
+				QueryString: "session_id=1&page=2",
+				Cookies:     "session=abc; theme=dark",
+				Headers:     map[string]string{"X-Api-Key": "abc", "Accept": "*/*"},
+			}
+			return event
+		})
+	})
+	obs.CaptureMessage(ctx, "request")
+
+	request := transport.Events()[0].Request
+	require.JSONEq(t, `{"email":"a@b.c","password":"***","nested":{"refresh_token":"***"}}`, request.Data)
+	require.Equal(t, "page=2&session_id=%2A%2A%2A", request.QueryString)
+	require.Equal(t, "session=***; theme=dark", request.Cookies)
+	require.Equal(t, map[string]string{"X-Api-Key": "***", "Accept": "*/*"}, request.Headers)
+}
+
+func TestScrubberMiddlewareCookies(t *testing.T) {
+	obs, transport := newTestObsWithConfig(t, SentryConfig{SendDefaultPII: true})
+
+	handler := obs.HTTPMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Cookie", "sessionid=abc; lang=en")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	request := transport.Events()[0].Request
+	require.Equal(t, "sessionid=***; lang=en", request.Cookies)
+	require.Equal(t, "***", request.Headers["Cookie"])
+}
+
+func TestScrubberDropsErrors(t *testing.T) {
+	var seen int
+	obs, transport := newTestObsWithConfig(t, SentryConfig{
+		DropErrors:     []error{context.Canceled},
+		DropErrorTypes: []error{&fs.PathError{}},
+		BeforeSend: func(event *sentry.Event, hint *sentry.EventHint) *sentry.Event {
+			seen++
+			return event
+		},
+	})
+
+	ctx := context.Background()
+	require.Nil(t, obs.CaptureError(ctx, context.Canceled))
+	require.Nil(t, obs.CaptureError(ctx, errors.Join(errors.New("request"), context.Canceled)))
+	require.Nil(t, obs.CaptureError(ctx, &fs.PathError{Op: "open", Path: "/tmp/x", Err: fs.ErrNotExist}))
+	require.NotNil(t, obs.CaptureError(ctx, fs.ErrNotExist))
+
+	require.Len(t, transport.Events(), 1)
+	require.Equal(t, 1, seen)
+}
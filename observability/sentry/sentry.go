@@ -1,27 +1,180 @@
 package sentry
 
 import (
+	"context"
+	"net/http"
+	"regexp"
 	"time"
 
 	"github.com/getsentry/sentry-go"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
+const maxBreadcrumbs = 100
+
+// SentryConfig configures the sentry client. The zero value is safe for
+// production: SDK debug logs and PII are off and every error is sent.
 type SentryConfig struct {
 	DSN string
+	// Environment and Release tag every event, they default to the
+	// SENTRY_ENVIRONMENT and SENTRY_RELEASE variables
+	Environment string
+	Release     string
+	// ServerName defaults to the hostname
+	ServerName string
+	// SampleRate of error events sent, between 0 and 1, 0 sends every event
+	SampleRate float64
+	// TracesSampleRate of transactions sent, between 0 and 1, 0 disables
+	// performance monitoring. Transactions come from the OpenTelemetry spans
+	// seen by SpanProcessor.
+	TracesSampleRate float64
+	// Debug prints the SDK logs to stderr
+	Debug bool
+	// SendDefaultPII attaches the user IP, cookies and headers to events
+	SendDefaultPII bool
+	// AttachStacktrace adds a stack trace to messages, errors always have one
+	AttachStacktrace bool
+	// MaxBreadcrumbs kept per event, up to 100, 0 uses 100 and a negative
+	// value disables breadcrumbs
+	MaxBreadcrumbs int
+	// IgnoreErrors drops errors whose message matches any of these regular
+	// expressions
+	IgnoreErrors []string
+	// SensitiveKeywords mask extras, contexts, tags, request data, headers,
+	// cookies and breadcrumb data whose key contains any of them, on top of
+	// common names such as password, token or authorization
+	SensitiveKeywords []string
+	// SensitivePatterns mask any string value they match, including messages
+	SensitivePatterns []*regexp.Regexp
+	// ScrubMessages also masks keyword=value pairs inside messages, exception
+	// values and breadcrumb messages
+	ScrubMessages bool
+	// DropErrors drops the events of errors matching any of them with errors.Is
+	DropErrors []error
+	// DropErrorTypes drops the events of errors whose chain holds an error of
+	// the same type as any of them, e.g. &net.OpError{}
+	DropErrorTypes []error
+	// BeforeSend runs after the events were scrubbed, returning nil drops
+	// the event
+	BeforeSend func(event *sentry.Event, hint *sentry.EventHint) *sentry.Event
+}
+
+// Validate reports the first invalid value of the config
+func (cfg SentryConfig) Validate() error {
+	if cfg.SampleRate < 0 || cfg.SampleRate > 1 {
+		return errors.Errorf("sentry sample rate must be between 0 and 1, got %v", cfg.SampleRate)
+	}
+
+	if cfg.TracesSampleRate < 0 || cfg.TracesSampleRate > 1 {
+		return errors.Errorf("sentry traces sample rate must be between 0 and 1, got %v", cfg.TracesSampleRate)
+	}
+
+	if cfg.MaxBreadcrumbs > maxBreadcrumbs {
+		return errors.Errorf("sentry max breadcrumbs must be at most %d, got %d", maxBreadcrumbs, cfg.MaxBreadcrumbs)
+	}
+
+	for _, pattern := range cfg.IgnoreErrors {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return errors.Wrapf(err, "invalid sentry ignore error pattern %q", pattern)
+		}
+	}
+
+	return nil
+}
+
+func (cfg SentryConfig) clientOptions() sentry.ClientOptions {
+	scrub := newScrubber(cfg)
+
+	return sentry.ClientOptions{
+		Dsn:                   cfg.DSN,
+		Environment:           cfg.Environment,
+		Release:               cfg.Release,
+		ServerName:            cfg.ServerName,
+		SampleRate:            cfg.SampleRate,
+		EnableTracing:         cfg.TracesSampleRate > 0,
+		TracesSampleRate:      cfg.TracesSampleRate,
+		Debug:                 cfg.Debug,
+		SendDefaultPII:        cfg.SendDefaultPII,
+		AttachStacktrace:      cfg.AttachStacktrace,
+		MaxBreadcrumbs:        cfg.MaxBreadcrumbs,
+		IgnoreErrors:          cfg.IgnoreErrors,
+		BeforeSend:            scrub.beforeSend,
+		BeforeSendTransaction: scrub.beforeSendTransaction,
+	}
+}
+
+// SentryInterface defines the interface for error reporting operations
+type SentryInterface interface {
+	Flush()
+	WithHub(ctx context.Context) context.Context
+	Hub(ctx context.Context) *sentry.Hub
+	ConfigureScope(ctx context.Context, f func(scope *sentry.Scope))
+	SetTag(ctx context.Context, key, value string)
+	SetUser(ctx context.Context, user sentry.User)
+	CaptureError(ctx context.Context, err error, opts ...CaptureOption) *sentry.EventID
+	CaptureMessage(ctx context.Context, msg string, opts ...CaptureOption) *sentry.EventID
+	AddBreadcrumb(ctx context.Context, category, message string, data map[string]interface{})
+	HTTPMiddleware(opts ...MiddlewareOption) func(http.Handler) http.Handler
+	SpanProcessor() sdktrace.SpanProcessor
+	Propagator() propagation.TextMapPropagator
 }
 
 type SentryObs struct {
 	hub *sentry.Hub
 	cfg SentryConfig
+	// transport replaces the HTTP transport when set through WithTransport
+	transport sentry.Transport
+	// local keeps the client on its own hub instead of the global one
+	local bool
 }
 
-func NewSentryObs(cfg SentryConfig) (*SentryObs, error) {
-	err := sentry.Init(sentry.ClientOptions{
-		Dsn:            cfg.DSN,
-		Debug:          true,
-		SendDefaultPII: true,
-	})
+// Option customizes the SentryObs built by NewSentryObs
+type Option func(*SentryObs)
+
+// WithTransport sends events through transport instead of HTTP, see
+// sentrytest.Transport
+func WithTransport(transport sentry.Transport) Option {
+	return func(s *SentryObs) {
+		s.transport = transport
+	}
+}
+
+// WithLocalHub binds the client to a hub of its own instead of calling
+// sentry.Init, the sentry-go globals are left untouched
+func WithLocalHub() Option {
+	return func(s *SentryObs) {
+		s.local = true
+	}
+}
+
+func NewSentryObs(cfg SentryConfig, opts ...Option) (*SentryObs, error) {
+	s := &SentryObs{cfg: cfg}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid sentry config")
+	}
+
+	options := cfg.clientOptions()
+	if s.transport != nil {
+		options.Transport = s.transport
+	}
+
+	if s.local {
+		client, err := sentry.NewClient(options)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to setup sentry")
+		}
+
+		s.hub = sentry.NewHub(client, sentry.NewScope())
+		return s, nil
+	}
+
+	err := sentry.Init(options)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to setup sentry")
 	}
@@ -31,37 +184,56 @@ func NewSentryObs(cfg SentryConfig) (*SentryObs, error) {
 		return nil, errors.New("failed to get sentry hub")
 	}
 
-	return &SentryObs{
-		hub: sentryHub,
-		cfg: cfg,
-	}, nil
+	s.hub = sentryHub
+	return s, nil
 }
 
+// Flush waits up to 2 seconds for the buffered events to be sent
 func (s *SentryObs) Flush() {
-	sentry.Flush(2 * time.Second)
+	s.hub.Flush(2 * time.Second)
 }
 
-func (s *SentryObs) CaptureError(err error, status_code int) *sentry.EventID {
-	var event_id *sentry.EventID
+// WithHub returns a copy of ctx holding a hub cloned from the one already in
+// ctx, or from the base hub. Call it once per request or goroutine so tags
+// and users set on its scope stay isolated from concurrent work.
+func (s *SentryObs) WithHub(ctx context.Context) context.Context {
+	return sentry.SetHubOnContext(ctx, s.hubFrom(ctx).Clone())
+}
 
-	s.hub.WithScope(func(scope *sentry.Scope) {
-		scope.SetLevel(sentry.LevelError)
-		scope.SetExtra("status_code", status_code)
+// Hub returns the hub stored in ctx by WithHub, or a clone of the base hub so
+// callers never mutate the shared scope
+func (s *SentryObs) Hub(ctx context.Context) *sentry.Hub {
+	if hub := sentry.GetHubFromContext(ctx); hub != nil {
+		return hub
+	}
+	return s.hub.Clone()
+}
 
-		type metadataError interface {
-			GetMetadata() map[string]interface{}
-		}
+// ConfigureScope changes the scope of the hub stored in ctx, it is a no-op
+// without one
+func (s *SentryObs) ConfigureScope(ctx context.Context, f func(scope *sentry.Scope)) {
+	if hub := sentry.GetHubFromContext(ctx); hub != nil {
+		hub.ConfigureScope(f)
+	}
+}
 
-		var mdErr metadataError
-		if errors.As(err, &mdErr) {
-			metadata := mdErr.GetMetadata()
-			for key, value := range metadata {
-				scope.SetExtra(key, value)
-			}
-		}
+// SetTag adds a tag to every event captured with ctx
+func (s *SentryObs) SetTag(ctx context.Context, key, value string) {
+	s.ConfigureScope(ctx, func(scope *sentry.Scope) {
+		scope.SetTag(key, value)
+	})
+}
 
-		event_id = s.hub.CaptureException(err)
+// SetUser attaches user to every event captured with ctx
+func (s *SentryObs) SetUser(ctx context.Context, user sentry.User) {
+	s.ConfigureScope(ctx, func(scope *sentry.Scope) {
+		scope.SetUser(user)
 	})
+}
 
-	return event_id
+func (s *SentryObs) hubFrom(ctx context.Context) *sentry.Hub {
+	if hub := sentry.GetHubFromContext(ctx); hub != nil {
+		return hub
+	}
+	return s.hub
 }
@@ -0,0 +1,64 @@
+package sentry
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/getsentry/sentry-go"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+type MockSentry struct{}
+
+func NewMockSentry() MockSentry {
+	return MockSentry{}
+}
+
+func (m MockSentry) Flush() {}
+
+func (m MockSentry) WithHub(ctx context.Context) context.Context {
+	return ctx
+}
+
+func (m MockSentry) Hub(ctx context.Context) *sentry.Hub {
+	return sentry.NewHub(nil, sentry.NewScope())
+}
+
+func (m MockSentry) ConfigureScope(ctx context.Context, f func(scope *sentry.Scope)) {}
+
+func (m MockSentry) SetTag(ctx context.Context, key, value string) {}
+
+func (m MockSentry) SetUser(ctx context.Context, user sentry.User) {}
+
+func (m MockSentry) CaptureError(ctx context.Context, err error, opts ...CaptureOption) *sentry.EventID {
+	return nil
+}
+
+func (m MockSentry) CaptureMessage(ctx context.Context, msg string, opts ...CaptureOption) *sentry.EventID {
+	return nil
+}
+
+func (m MockSentry) AddBreadcrumb(ctx context.Context, category, message string, data map[string]interface{}) {
+}
+
+func (m MockSentry) HTTPMiddleware(opts ...MiddlewareOption) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return next
+	}
+}
+
+func (m MockSentry) SpanProcessor() sdktrace.SpanProcessor {
+	return noopSpanProcessor{}
+}
+
+func (m MockSentry) Propagator() propagation.TextMapPropagator {
+	return propagation.NewCompositeTextMapPropagator()
+}
+
+type noopSpanProcessor struct{}
+
+func (noopSpanProcessor) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+func (noopSpanProcessor) OnEnd(sdktrace.ReadOnlySpan)                     {}
+func (noopSpanProcessor) Shutdown(context.Context) error                  { return nil }
+func (noopSpanProcessor) ForceFlush(context.Context) error                { return nil }
@@ -0,0 +1,181 @@
+package sentry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// eventsTransport keeps the events sent by a client in memory
+type eventsTransport struct {
+	mu     sync.Mutex
+	events []*sentry.Event
+}
+
+func (tr *eventsTransport) Flush(time.Duration) bool              { return true }
+func (tr *eventsTransport) FlushWithContext(context.Context) bool { return true }
+func (tr *eventsTransport) Configure(sentry.ClientOptions)        {}
+func (tr *eventsTransport) Close()                                {}
+func (tr *eventsTransport) SendEvent(event *sentry.Event) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.events = append(tr.events, event)
+}
+
+func (tr *eventsTransport) Events() []*sentry.Event {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	return append([]*sentry.Event(nil), tr.events...)
+}
+
+func newTestObs(t *testing.T) (*SentryObs, *eventsTransport) {
+	return newTestObsWithConfig(t, SentryConfig{})
+}
+
+func newTestObsWithConfig(t *testing.T, cfg SentryConfig) (*SentryObs, *eventsTransport) {
+	transport := &eventsTransport{}
+
+	obs, err := NewSentryObs(cfg, WithTransport(transport), WithLocalHub())
+	require.NoError(t, err)
+
+	return obs, transport
+}
+
+func TestSentryConfigValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  SentryConfig
+		err  string
+	}{
+		{name: "zero value", cfg: SentryConfig{}},
+		{name: "full", cfg: SentryConfig{SampleRate: 0.5, MaxBreadcrumbs: 50, IgnoreErrors: []string{"^context canceled$"}}},
+		{name: "breadcrumbs disabled", cfg: SentryConfig{MaxBreadcrumbs: -1}},
+		{name: "negative sample rate", cfg: SentryConfig{SampleRate: -0.1}, err: "sample rate"},
+		{name: "sample rate above 1", cfg: SentryConfig{SampleRate: 1.5}, err: "sample rate"},
+		{name: "traces sample rate above 1", cfg: SentryConfig{TracesSampleRate: 1.5}, err: "traces sample rate"},
+		{name: "too many breadcrumbs", cfg: SentryConfig{MaxBreadcrumbs: 101}, err: "max breadcrumbs"},
+		{name: "invalid pattern", cfg: SentryConfig{IgnoreErrors: []string{"("}}, err: "ignore error pattern"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestNewSentryObsSafeDefaults(t *testing.T) {
+	t.Setenv("SENTRY_DSN", "")
+
+	obs, err := NewSentryObs(SentryConfig{
+		Environment:  "production",
+		Release:      "users@1.4.2",
+		ServerName:   "users-0",
+		IgnoreErrors: []string{"context canceled"},
+	})
+	require.NoError(t, err)
+
+	options := obs.hub.Client().Options()
+	require.False(t, options.Debug)
+	require.False(t, options.SendDefaultPII)
+	require.Equal(t, 1.0, options.SampleRate)
+	require.Equal(t, "production", options.Environment)
+	require.Equal(t, "users@1.4.2", options.Release)
+	require.Equal(t, "users-0", options.ServerName)
+	require.Equal(t, []string{"context canceled"}, options.IgnoreErrors)
+}
+
+func TestNewSentryObsRejectsInvalidConfig(t *testing.T) {
+	_, err := NewSentryObs(SentryConfig{SampleRate: 2})
+	require.ErrorContains(t, err, "invalid sentry config")
+}
+
+func TestRequestHubsAreIsolated(t *testing.T) {
+	obs, transport := newTestObs(t)
+
+	var wg sync.WaitGroup
+	for _, user := range []string{"alice", "bob"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ctx := obs.WithHub(context.Background())
+			obs.SetUser(ctx, sentry.User{ID: user})
+			obs.SetTag(ctx, "tenant", user+"-tenant")
+
+			require.NotNil(t, obs.CaptureError(ctx, errors.New(user+" failed"), WithStatusCode(500)))
+		}()
+	}
+	wg.Wait()
+
+	events := transport.Events()
+	require.Len(t, events, 2)
+	for _, event := range events {
+		user := event.User.ID
+		require.Equal(t, user+" failed", event.Exception[0].Value)
+		require.Equal(t, user+"-tenant", event.Tags["tenant"])
+		require.Equal(t, 500, event.Extra["status_code"])
+	}
+
+	// the base hub scope is untouched
+	require.NotNil(t, obs.CaptureError(context.Background(), errors.New("background"), WithStatusCode(500)))
+	events = transport.Events()
+	require.Empty(t, events[2].User.ID)
+	require.Empty(t, events[2].Tags["tenant"])
+}
+
+func TestWithHubInheritsParentScope(t *testing.T) {
+	obs, transport := newTestObs(t)
+
+	ctx := obs.WithHub(context.Background())
+	obs.SetTag(ctx, "request", "1")
+
+	job := obs.WithHub(ctx)
+	obs.SetTag(job, "job", "emails")
+	require.NotSame(t, obs.Hub(ctx), obs.Hub(job))
+
+	obs.CaptureError(job, errors.New("job failed"), WithStatusCode(500))
+	obs.CaptureError(ctx, errors.New("request failed"), WithStatusCode(500))
+
+	events := transport.Events()
+	require.Len(t, events, 2)
+	require.Equal(t, map[string]string{"request": "1", "job": "emails"}, events[0].Tags)
+	require.Equal(t, map[string]string{"request": "1"}, events[1].Tags)
+}
+
+func TestCaptureErrorLinksTrace(t *testing.T) {
+	obs, transport := newTestObs(t)
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(obs.WithHub(context.Background()), sc)
+
+	event_id := obs.CaptureError(ctx, errors.New("boom"), WithStatusCode(500))
+	require.NotNil(t, event_id)
+
+	events := transport.Events()
+	require.Len(t, events, 1)
+	require.Equal(t, *event_id, events[0].EventID)
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", events[0].Tags["trace_id"])
+	require.Equal(t, "00f067aa0ba902b7", events[0].Tags["span_id"])
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", events[0].Contexts["trace"]["trace_id"].(sentry.TraceID).String())
+	require.Equal(t, "00f067aa0ba902b7", events[0].Contexts["trace"]["span_id"].(sentry.SpanID).String())
+
+	// without a span the event is not tagged
+	obs.CaptureError(context.Background(), errors.New("untraced"), WithStatusCode(500))
+	require.NotContains(t, transport.Events()[1].Tags, "trace_id")
+}
@@ -0,0 +1,230 @@
+// Package sentrytest provides an in-memory Sentry transport and a recorder
+// implementing sentry.SentryInterface so tests can assert on the events
+// their code reports without a network.
+package sentrytest
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bolanosdev/go-snacks/observability/sentry"
+	sentrygo "github.com/getsentry/sentry-go"
+)
+
+// Transport is a sentry-go transport keeping every event in memory
+type Transport struct {
+	mu     sync.Mutex
+	events []*sentrygo.Event
+}
+
+func NewTransport() *Transport {
+	return &Transport{}
+}
+
+func (t *Transport) Configure(sentrygo.ClientOptions) {}
+
+func (t *Transport) SendEvent(event *sentrygo.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, event)
+}
+
+func (t *Transport) Flush(time.Duration) bool { return true }
+
+func (t *Transport) FlushWithContext(context.Context) bool { return true }
+
+func (t *Transport) Close() {}
+
+// Events returns every event sent so far, in the order they were captured
+func (t *Transport) Events() []*sentrygo.Event {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*sentrygo.Event(nil), t.events...)
+}
+
+// Reset drops the recorded events
+func (t *Transport) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = nil
+}
+
+// Recorder is a sentry.SentryInterface backed by a real client, with its own
+// hub, that keeps every event in memory. Events go through the same
+// scrubbing as in production.
+type Recorder struct {
+	*sentry.SentryObs
+
+	tb        testing.TB
+	transport *Transport
+}
+
+// NewRecorder creates a Recorder with the default SentryConfig
+func NewRecorder(tb testing.TB) *Recorder {
+	return NewRecorderWithConfig(tb, sentry.SentryConfig{})
+}
+
+// NewRecorderWithConfig creates a Recorder honoring cfg, its DSN is ignored
+func NewRecorderWithConfig(tb testing.TB, cfg sentry.SentryConfig) *Recorder {
+	tb.Helper()
+
+	transport := NewTransport()
+	cfg.DSN = ""
+
+	obs, err := sentry.NewSentryObs(cfg, sentry.WithTransport(transport), sentry.WithLocalHub())
+	if err != nil {
+		tb.Fatalf("failed to create sentry recorder: %v", err)
+	}
+
+	return &Recorder{
+		SentryObs: obs,
+		tb:        tb,
+		transport: transport,
+	}
+}
+
+// Transport returns the transport events are recorded by
+func (r *Recorder) Transport() *Transport {
+	return r.transport
+}
+
+// Events returns every event captured so far, in the order they were captured
+func (r *Recorder) Events() []*sentrygo.Event {
+	return r.transport.Events()
+}
+
+// Reset drops the recorded events
+func (r *Recorder) Reset() {
+	r.transport.Reset()
+}
+
+// FindEvent returns the first event whose message, or error message for
+// captured errors, is message
+func (r *Recorder) FindEvent(message string) (*sentrygo.Event, bool) {
+	for _, event := range r.transport.Events() {
+		if EventMessage(event) == message {
+			return event, true
+		}
+	}
+	return nil, false
+}
+
+// RequireEvent returns the event with message and stops the test when it
+// was never captured
+func (r *Recorder) RequireEvent(message string) *sentrygo.Event {
+	r.tb.Helper()
+
+	event, ok := r.FindEvent(message)
+	if !ok {
+		r.tb.Fatalf("event %q was not captured, got %v", message, r.messages())
+	}
+	return event
+}
+
+// AssertMessage checks that an event with message was captured
+func (r *Recorder) AssertMessage(message string) bool {
+	r.tb.Helper()
+
+	if _, ok := r.FindEvent(message); !ok {
+		r.tb.Errorf("event %q was not captured, got %v", message, r.messages())
+		return false
+	}
+	return true
+}
+
+// AssertNoEvents checks that nothing was captured
+func (r *Recorder) AssertNoEvents() bool {
+	r.tb.Helper()
+
+	if messages := r.messages(); len(messages) > 0 {
+		r.tb.Errorf("expected no events, got %v", messages)
+		return false
+	}
+	return true
+}
+
+// AssertLevel checks the level of the event with message
+func (r *Recorder) AssertLevel(message string, level sentrygo.Level) bool {
+	r.tb.Helper()
+
+	event, ok := r.FindEvent(message)
+	if !ok {
+		r.tb.Errorf("event %q was not captured, got %v", message, r.messages())
+		return false
+	}
+
+	if event.Level != level {
+		r.tb.Errorf("event %q level: expected %s, got %s", message, level, event.Level)
+		return false
+	}
+	return true
+}
+
+// AssertTag checks that the event with message carries the tag key = value
+func (r *Recorder) AssertTag(message, key, value string) bool {
+	r.tb.Helper()
+
+	event, ok := r.FindEvent(message)
+	if !ok {
+		r.tb.Errorf("event %q was not captured, got %v", message, r.messages())
+		return false
+	}
+
+	actual, ok := event.Tags[key]
+	if !ok {
+		r.tb.Errorf("event %q has no tag %q", message, key)
+		return false
+	}
+	if actual != value {
+		r.tb.Errorf("event %q tag %q: expected %q, got %q", message, key, value, actual)
+		return false
+	}
+	return true
+}
+
+// AssertExtra checks that the event with message carries the extra key with
+// a value deeply equal to value
+func (r *Recorder) AssertExtra(message, key string, value interface{}) bool {
+	r.tb.Helper()
+
+	event, ok := r.FindEvent(message)
+	if !ok {
+		r.tb.Errorf("event %q was not captured, got %v", message, r.messages())
+		return false
+	}
+
+	actual, ok := event.Extra[key]
+	if !ok {
+		r.tb.Errorf("event %q has no extra %q", message, key)
+		return false
+	}
+	if !reflect.DeepEqual(actual, value) {
+		r.tb.Errorf("event %q extra %q: expected %#v, got %#v", message, key, value, actual)
+		return false
+	}
+	return true
+}
+
+// EventMessage returns the message of event, or the message of the outermost
+// error for captured errors
+func EventMessage(event *sentrygo.Event) string {
+	if event.Message != "" {
+		return event.Message
+	}
+	if n := len(event.Exception); n > 0 {
+		return event.Exception[n-1].Value
+	}
+	return ""
+}
+
+func (r *Recorder) messages() []string {
+	events := r.transport.Events()
+	messages := make([]string, len(events))
+	for i, event := range events {
+		messages[i] = EventMessage(event)
+	}
+	return messages
+}
@@ -0,0 +1,91 @@
+package sentrytest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bolanosdev/go-snacks/observability/sentry"
+	sentrygo "github.com/getsentry/sentry-go"
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorderScrubsEvents(t *testing.T) {
+	var obs sentry.SentryInterface = NewRecorder(t)
+	recorder := obs.(*Recorder)
+
+	ctx := recorder.WithHub(context.Background())
+	recorder.CaptureError(ctx, errors.New("charge failed"), sentry.WithExtra("password", "hunter2"), sentry.WithStatusCode(402))
+
+	recorder.AssertExtra("charge failed", "password", "***")
+	recorder.AssertExtra("charge failed", "status_code", 402)
+	recorder.AssertLevel("charge failed", sentrygo.LevelError)
+}
+
+func TestRecorderIsolatesRequestHubs(t *testing.T) {
+	recorder := NewRecorder(t)
+
+	alice := recorder.WithHub(context.Background())
+	recorder.SetTag(alice, "user", "alice")
+	recorder.AddBreadcrumb(alice, "cart", "alice added an item", nil)
+
+	bob := recorder.WithHub(context.Background())
+	recorder.SetTag(bob, "user", "bob")
+
+	recorder.CaptureMessage(alice, "alice checkout", sentry.WithLevel(sentrygo.LevelWarning))
+	recorder.CaptureMessage(bob, "bob checkout")
+
+	recorder.AssertTag("alice checkout", "user", "alice")
+	recorder.AssertTag("bob checkout", "user", "bob")
+	recorder.AssertLevel("alice checkout", sentrygo.LevelWarning)
+	recorder.AssertLevel("bob checkout", sentrygo.LevelInfo)
+
+	require.Len(t, recorder.RequireEvent("alice checkout").Breadcrumbs, 1)
+	require.Empty(t, recorder.RequireEvent("bob checkout").Breadcrumbs)
+
+	recorder.Reset()
+	recorder.AssertNoEvents()
+}
+
+func TestRecorderMiddleware(t *testing.T) {
+	recorder := NewRecorder(t)
+
+	handler := recorder.HTTPMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder.SetTag(r.Context(), "tenant", "acme")
+		panic("boom")
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users", nil))
+
+	recorder.AssertLevel("boom", sentrygo.LevelFatal)
+	recorder.AssertTag("boom", "tenant", "acme")
+	require.Equal(t, "http://example.com/users", recorder.RequireEvent("boom").Request.URL)
+}
+
+func TestEventMessage(t *testing.T) {
+	recorder := NewRecorder(t)
+	ctx := context.Background()
+
+	// the outermost error names the event, not the root cause
+	recorder.CaptureError(ctx, pkgerrors.Wrap(errors.New("connection refused"), "failed to load user"))
+	recorder.CaptureMessage(ctx, "quota low")
+
+	events := recorder.Events()
+	require.Len(t, events, 2)
+	require.Equal(t, "failed to load user: connection refused", EventMessage(events[0]))
+	require.Equal(t, "quota low", EventMessage(events[1]))
+	require.Empty(t, EventMessage(&sentrygo.Event{}))
+
+	_, ok := recorder.FindEvent("connection refused")
+	require.False(t, ok)
+}
+
+func TestRecorderWithConfig(t *testing.T) {
+	recorder := NewRecorderWithConfig(t, sentry.SentryConfig{DropErrors: []error{context.Canceled}})
+
+	recorder.CaptureError(context.Background(), context.Canceled)
+	recorder.AssertNoEvents()
+	require.Empty(t, recorder.Transport().Events())
+}
@@ -0,0 +1,25 @@
+package sentry
+
+import (
+	"context"
+
+	"github.com/getsentry/sentry-go"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// linkTrace tags the events of scope with the OpenTelemetry trace and span
+// of ctx, matching the trace_id written by ContextLogger, and uses them as
+// the Sentry trace context so the issue links to the trace
+func linkTrace(ctx context.Context, scope *sentry.Scope) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+
+	scope.SetTag("trace_id", sc.TraceID().String())
+	scope.SetTag("span_id", sc.SpanID().String())
+	scope.SetPropagationContext(sentry.PropagationContext{
+		TraceID: sentry.TraceID(sc.TraceID()),
+		SpanID:  sentry.SpanID(sc.SpanID()),
+	})
+}
@@ -0,0 +1,72 @@
+package sentry
+
+import (
+	"context"
+
+	"github.com/getsentry/sentry-go"
+	sentryotel "github.com/getsentry/sentry-go/otel"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// SpanProcessor turns the OpenTelemetry spans into Sentry transactions and
+// spans, register it on the tracer provider together with Propagator, see
+// jaeger.JaegerConfig.SpanProcessors. Transactions are sampled with
+// SentryConfig.TracesSampleRate and reported with the hub of the context
+// the root span is started from, or a clone of the base hub.
+func (s *SentryObs) SpanProcessor() sdktrace.SpanProcessor {
+	return spanProcessor{SpanProcessor: sentryotel.NewSentrySpanProcessor(), obs: s}
+}
+
+// Propagator reads and writes the sentry-trace and baggage headers so
+// transactions continue across services, OpenTelemetry baggage members are
+// kept in the baggage header
+func (s *SentryObs) Propagator() propagation.TextMapPropagator {
+	return propagator{TextMapPropagator: sentryotel.NewSentryPropagator()}
+}
+
+// spanProcessor binds the sentry-go processor, which only knows about the
+// global hub, to the hub of the SentryObs
+type spanProcessor struct {
+	sdktrace.SpanProcessor
+	obs *SentryObs
+}
+
+func (p spanProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	p.SpanProcessor.OnStart(sentry.SetHubOnContext(parent, p.obs.Hub(parent)), s)
+}
+
+func (p spanProcessor) ForceFlush(ctx context.Context) error {
+	return p.SpanProcessor.ForceFlush(sentry.SetHubOnContext(ctx, p.obs.hub))
+}
+
+func (p spanProcessor) Shutdown(ctx context.Context) error {
+	return p.SpanProcessor.Shutdown(sentry.SetHubOnContext(ctx, p.obs.hub))
+}
+
+// propagator merges the OpenTelemetry baggage of ctx back into the baggage
+// header the sentry propagator overwrites
+type propagator struct {
+	propagation.TextMapPropagator
+}
+
+func (p propagator) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	p.TextMapPropagator.Inject(ctx, carrier)
+
+	members := baggage.FromContext(ctx).Members()
+	if len(members) == 0 {
+		return
+	}
+
+	bag, err := baggage.Parse(carrier.Get(sentry.SentryBaggageHeader))
+	if err != nil {
+		bag = baggage.Baggage{}
+	}
+	for _, member := range members {
+		if merged, err := bag.SetMember(member); err == nil {
+			bag = merged
+		}
+	}
+	carrier.Set(sentry.SentryBaggageHeader, bag.String())
+}
@@ -0,0 +1,89 @@
+package sentry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func newTestTracerProvider(t *testing.T, obs *SentryObs) *sdktrace.TracerProvider {
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(obs.SpanProcessor()))
+	t.Cleanup(func() {
+		tp.Shutdown(context.Background())
+	})
+	return tp
+}
+
+func TestSpanProcessorSendsTransactions(t *testing.T) {
+	obs, transport := newTestObsWithConfig(t, SentryConfig{TracesSampleRate: 1})
+	tracer := newTestTracerProvider(t, obs).Tracer("test")
+
+	ctx, root := tracer.Start(context.Background(), "GET /users")
+	_, child := tracer.Start(ctx, "SELECT users")
+	child.SetAttributes(attribute.String("db.statement", "SELECT * FROM users"), attribute.String("auth_token", "abc"))
+	obs.CaptureError(ctx, errors.New("slow query"))
+	child.End()
+	root.End()
+
+	events := transport.Events()
+	require.Len(t, events, 2)
+
+	errEvent, transaction := events[0], events[1]
+	require.Equal(t, "transaction", transaction.Type)
+	require.Equal(t, "GET /users", transaction.Transaction)
+	require.Equal(t, root.SpanContext().TraceID().String(), transaction.Contexts["trace"]["trace_id"].(sentry.TraceID).String())
+
+	require.Len(t, transaction.Spans, 1)
+	require.Equal(t, "SELECT users", transaction.Spans[0].Description)
+	require.Equal(t, "***", transaction.Spans[0].Data["auth_token"])
+	require.Equal(t, "SELECT * FROM users", transaction.Spans[0].Data["db.statement"])
+
+	// errors captured inside a span belong to the same trace
+	require.Equal(t, root.SpanContext().TraceID().String(), errEvent.Tags["trace_id"])
+}
+
+func TestSpanProcessorDisabledWithoutSampleRate(t *testing.T) {
+	obs, transport := newTestObs(t)
+	tracer := newTestTracerProvider(t, obs).Tracer("test")
+
+	_, span := tracer.Start(context.Background(), "GET /users")
+	span.End()
+
+	require.Empty(t, transport.Events())
+}
+
+func TestPropagatorKeepsBaggage(t *testing.T) {
+	obs, _ := newTestObsWithConfig(t, SentryConfig{TracesSampleRate: 1})
+	tracer := newTestTracerProvider(t, obs).Tracer("test")
+
+	member, err := baggage.NewMember("tenant.id", "acme")
+	require.NoError(t, err)
+	bag, err := baggage.New(member)
+	require.NoError(t, err)
+
+	ctx, span := tracer.Start(baggage.ContextWithBaggage(context.Background(), bag), "GET /users")
+	defer span.End()
+
+	carrier := propagation.MapCarrier{}
+	obs.Propagator().Inject(ctx, carrier)
+
+	require.Regexp(t, "^"+span.SpanContext().TraceID().String()+"-"+span.SpanContext().SpanID().String()+"-1$", carrier.Get(sentry.SentryTraceHeader))
+
+	header, err := baggage.Parse(carrier.Get(sentry.SentryBaggageHeader))
+	require.NoError(t, err)
+	require.Equal(t, "acme", header.Member("tenant.id").Value())
+	require.Equal(t, span.SpanContext().TraceID().String(), header.Member("sentry-trace_id").Value())
+
+	// a downstream service continues the trace
+	extracted := obs.Propagator().Extract(context.Background(), carrier)
+	_, downstream := tracer.Start(extracted, "downstream")
+	defer downstream.End()
+	require.Equal(t, span.SpanContext().TraceID(), downstream.SpanContext().TraceID())
+}